@@ -0,0 +1,791 @@
+package main
+
+import (
+	"bufio"         // Reading lines.
+	"context"       // Stopping the server.
+	"encoding/json" // JSON output.
+	"errors"        // Error handling.
+	"flag"          // Command-line flags.
+	"fmt"           // String formatting.
+	"io"            // Readers and writers.
+	"os"            // Files.
+	"os/signal"     // Stopping the server.
+	"strings"       // String manipulation.
+	"syscall"       // Stopping the server.
+	"time"          // Default seed.
+
+	"github.com/maucarrui/Sudoku2Go/sudoku" // Puzzles.
+)
+
+// The exit codes of the command-line tool.
+const (
+	// Every puzzle was processed successfully.
+	exitOK = 0
+
+	// Some puzzle couldn't be read, has no solution or failed the
+	// validation. The other puzzles are still processed.
+	exitPuzzle = 1
+
+	// The command or its flags are wrong.
+	exitUsage = 2
+
+	// The input couldn't be read or the output couldn't be written.
+	exitIO = 3
+)
+
+const cliUsage = `Usage: sudoku <command> [flags] [file]
+
+Commands:
+  play      Play a puzzle on the terminal.
+  solve     Print the solution of every puzzle.
+  generate  Print new puzzles.
+  rate      Print the difficulty of every puzzle.
+  validate  Check that every puzzle has a unique solution and no conflicts.
+  convert   Translate puzzles between formats.
+  batch     Solve or rate large files of puzzles in parallel.
+  serve     Run the HTTP JSON API.
+  replay    Play back a game recorded with "play -record".
+
+Puzzles are read from the file, or from the standard input if there is none
+or it's "-". Run "sudoku <command> -h" for the flags of a command.
+
+Exit codes:
+  0  Every puzzle was processed successfully.
+  1  Some puzzle couldn't be read, has no solution or failed the validation.
+  2  The command or its flags are wrong.
+  3  The input couldn't be read or the output couldn't be written.
+`
+
+// The input formats of the command-line tool.
+var inputFormats = []string{"line", "sdk", "ss", "json", "fpuzzles", "binary"}
+
+// The output formats of the convert command.
+var outputFormats = []string{"line", "sdk", "ss", "json", "fpuzzles", "binary", "text", "ascii", "svg", "png", "pdf"}
+
+// A puzzle read by the command-line tool, with its position on the input
+// starting at 1 and the error found reading it, if any.
+type cliPuzzle struct {
+	number int
+	sudoku sudoku.Sudoku
+	err    error
+}
+
+// The state of a command: its flags and streams.
+type cliCommand struct {
+	flags  *flag.FlagSet
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// The format of the input, and "text" or "json" for the output.
+	format string
+	output string
+}
+
+// Returns true if the list contains the string.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reads the puzzles of the input in the given format. In the line format every
+// non-empty line not starting with '#' is a puzzle in one of the formats of
+// @Parse, and in the fpuzzles format every line is a link or a payload. The
+// sdk and ss formats hold a single puzzle, and the json and binary formats a
+// stream of puzzles. An error is only returned if the input can't be read;
+// puzzles which can't be parsed carry their own error.
+func readPuzzles(r io.Reader, format string) ([]cliPuzzle, error) {
+	var puzzles []cliPuzzle
+
+	switch format {
+	case "line", "fpuzzles":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			var puzzle sudoku.Sudoku
+			var err error
+
+			if format == "line" {
+				puzzle, err = sudoku.Parse(text)
+			} else {
+				puzzle, err = sudoku.DecodeFPuzzles(text)
+			}
+
+			puzzles = append(puzzles, cliPuzzle{line, puzzle, err})
+		}
+
+		return puzzles, scanner.Err()
+
+	case "sdk", "ss":
+		var puzzle sudoku.Sudoku
+		var err error
+
+		if format == "sdk" {
+			puzzle, err = sudoku.ReadSDK(r)
+		} else {
+			puzzle, err = sudoku.ReadSS(r)
+		}
+
+		return []cliPuzzle{{1, puzzle, err}}, nil
+
+	case "json":
+		decoder := json.NewDecoder(r)
+
+		for number := 1; ; number++ {
+			var raw json.RawMessage
+
+			if err := decoder.Decode(&raw); err == io.EOF {
+				return puzzles, nil
+			} else if err != nil {
+				return puzzles, err
+			}
+
+			var puzzle sudoku.Sudoku
+			err := json.Unmarshal(raw, &puzzle)
+
+			puzzles = append(puzzles, cliPuzzle{number, puzzle, err})
+		}
+
+	case "binary":
+		reader := sudoku.NewBinaryReader(bufio.NewReader(r))
+
+		for number := 1; ; number++ {
+			puzzle, err := reader.Read()
+
+			if err == io.EOF {
+				return puzzles, nil
+			} else if errors.Is(err, io.ErrUnexpectedEOF) {
+				return puzzles, err
+			}
+
+			puzzles = append(puzzles, cliPuzzle{number, puzzle, err})
+		}
+	}
+
+	return nil, fmt.Errorf("Sudoku: Unknown format %q.", format)
+}
+
+// Parses the flags of the command, adding -format and -output if io is set.
+// Returns the exit code and false if the command can't run.
+func (cmd *cliCommand) parse(args []string, io bool) (int, bool) {
+	if io {
+		cmd.flags.StringVar(&cmd.format, "format", "line", "format of the input: "+strings.Join(inputFormats, ", "))
+		cmd.flags.StringVar(&cmd.output, "output", "text", "format of the output: text or json")
+	}
+
+	if err := cmd.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, false
+		}
+
+		return exitUsage, false
+	}
+
+	if io && (!contains(inputFormats, cmd.format) || (cmd.output != "text" && cmd.output != "json")) {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown format.")
+		return exitUsage, false
+	}
+
+	if cmd.flags.NArg() > 1 {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Too many arguments.")
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
+
+// Returns the file given as argument, or the standard input if there is none,
+// and a function which closes it.
+func (cmd *cliCommand) input() (io.Reader, func(), error) {
+	path := cmd.flags.Arg(0)
+
+	if path == "" || path == "-" {
+		return cmd.stdin, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, func() { file.Close() }, nil
+}
+
+// Returns the puzzles of the file given as argument, or of the standard input.
+func (cmd *cliCommand) puzzles() ([]cliPuzzle, error) {
+	input, closeInput, err := cmd.input()
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeInput()
+
+	return readPuzzles(input, cmd.format)
+}
+
+// Writes the result of a puzzle, as the given text or as a JSON line of the
+// given value.
+func (cmd *cliCommand) write(text string, value interface{}) error {
+	if cmd.output == "json" {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		text = string(data)
+	}
+
+	_, err := fmt.Fprintln(cmd.stdout, text)
+
+	return err
+}
+
+// Runs fn on every puzzle of the input. Puzzles which can't be read, and those
+// for which fn returns an error, are reported on the standard error output.
+// Returns the exit code of the command.
+func (cmd *cliCommand) each(fn func(puzzle cliPuzzle) error) int {
+	puzzles, err := cmd.puzzles()
+	code := exitOK
+
+	for _, puzzle := range puzzles {
+		if puzzle.err == nil {
+			puzzle.err = fn(puzzle)
+		}
+
+		var writeErr *cliWriteError
+		if errors.As(puzzle.err, &writeErr) {
+			fmt.Fprintln(cmd.stderr, writeErr.err)
+			return exitIO
+		}
+
+		if puzzle.err != nil {
+			fmt.Fprintf(cmd.stderr, "%d: %v\n", puzzle.number, puzzle.err)
+			code = exitPuzzle
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	return code
+}
+
+// An error writing the output, which stops the command.
+type cliWriteError struct {
+	err error
+}
+
+func (err *cliWriteError) Error() string {
+	return err.err.Error()
+}
+
+// Wraps an error of the output so @each stops.
+func writeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &cliWriteError{err}
+}
+
+// The JSON output of the solve command.
+type solveResult struct {
+	Puzzle   string `json:"puzzle"`
+	Solution string `json:"solution"`
+	Unique   bool   `json:"unique"`
+}
+
+func (cmd *cliCommand) solve(args []string) int {
+	if code, ok := cmd.parse(args, true); !ok {
+		return code
+	}
+
+	return cmd.each(func(puzzle cliPuzzle) error {
+		count, solved := puzzle.sudoku.CountSolutions(2)
+
+		if count == 0 {
+			return errors.New("Sudoku: The puzzle has no solution.")
+		}
+
+		text := solved.Line()
+		result := solveResult{puzzle.sudoku.GivensLine(), text, count == 1}
+
+		return writeError(cmd.write(text, result))
+	})
+}
+
+// The JSON output of the rate command.
+type rateResult struct {
+	Puzzle     string `json:"puzzle"`
+	Difficulty string `json:"difficulty"`
+	Hardest    string `json:"hardest,omitempty"`
+	Steps      int    `json:"steps"`
+	Score      int    `json:"score"`
+}
+
+func (cmd *cliCommand) rate(args []string) int {
+	if code, ok := cmd.parse(args, true); !ok {
+		return code
+	}
+
+	return cmd.each(func(puzzle cliPuzzle) error {
+		rating, err := puzzle.sudoku.Rate()
+		if err != nil {
+			return err
+		}
+
+		text := fmt.Sprintf("%s %d", rating.Difficulty, rating.Score)
+		result := rateResult{puzzle.sudoku.GivensLine(), rating.Difficulty, rating.Hardest, rating.Steps, rating.Score}
+
+		return writeError(cmd.write(text, result))
+	})
+}
+
+func (cmd *cliCommand) validate(args []string) int {
+	requireMinimal := cmd.flags.Bool("minimal", false, "also fail puzzles which are not minimal")
+
+	if code, ok := cmd.parse(args, true); !ok {
+		return code
+	}
+
+	return cmd.each(func(puzzle cliPuzzle) error {
+		result := puzzle.sudoku.Validate()
+		result.Valid = result.Valid && (result.Minimal || !*requireMinimal)
+
+		var problems []string
+
+		switch result.Solutions {
+		case 0:
+			problems = append(problems, "no solution")
+		case 2:
+			problems = append(problems, "more than one solution")
+		}
+
+		if len(result.Conflicts) > 0 {
+			problems = append(problems, fmt.Sprintf("%d conflicts", len(result.Conflicts)))
+		}
+
+		if !result.Minimal && result.Solutions == 1 {
+			problems = append(problems, "not minimal")
+		}
+
+		text := "valid"
+		if !result.Valid {
+			text = "invalid"
+		}
+
+		if len(problems) > 0 {
+			text += ": " + strings.Join(problems, ", ")
+		}
+
+		if err := cmd.write(text, result); err != nil {
+			return writeError(err)
+		}
+
+		if !result.Valid {
+			return errors.New("Sudoku: The puzzle is not valid.")
+		}
+
+		return nil
+	})
+}
+
+func (cmd *cliCommand) generate(args []string) int {
+	difficulty := cmd.flags.String("difficulty", "easy", "easy, medium, hard or expert")
+	symmetry := cmd.flags.String("symmetry", sudoku.Rotational, "none, rotational, mirror or diagonal")
+	count := cmd.flags.Int("count", 1, "number of puzzles")
+	seed := cmd.flags.Int64("seed", 0, "seed of the generator, random if 0")
+	cmd.flags.StringVar(&cmd.output, "output", "text", "format of the output: text or json")
+
+	if code, ok := cmd.parse(args, false); !ok {
+		return code
+	}
+
+	level := sudoku.ParseDifficulty(*difficulty)
+
+	switch level {
+	case sudoku.Easy, sudoku.Medium, sudoku.Hard, sudoku.Expert:
+	default:
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown difficulty.")
+		return exitUsage
+	}
+
+	switch *symmetry {
+	case sudoku.NoSymmetry, sudoku.Rotational, sudoku.Mirror, sudoku.Diagonal:
+	default:
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown symmetry.")
+		return exitUsage
+	}
+
+	if cmd.output != "text" && cmd.output != "json" {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown format.")
+		return exitUsage
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	generator := sudoku.NewGenerator(*seed)
+
+	for k := 0; k < *count; k++ {
+		puzzle, err := generator.Generate(level, *symmetry)
+		if err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			return exitPuzzle
+		}
+
+		if err := cmd.write(puzzle.GivensLine(), puzzle); err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			return exitIO
+		}
+	}
+
+	return exitOK
+}
+
+func (cmd *cliCommand) convert(args []string) int {
+	to := cmd.flags.String("to", "line", "format of the output: "+strings.Join(outputFormats, ", "))
+
+	if code, ok := cmd.parse(args, true); !ok {
+		return code
+	}
+
+	if !contains(outputFormats, *to) {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown format.")
+		return exitUsage
+	}
+
+	// Formats which hold a single puzzle or draw all of them at once.
+	switch *to {
+	case "sdk", "ss", "svg", "png", "pdf":
+		puzzles, err := cmd.puzzles()
+		if err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			return exitIO
+		}
+
+		var sudokus []sudoku.Sudoku
+
+		for _, puzzle := range puzzles {
+			if puzzle.err != nil {
+				fmt.Fprintf(cmd.stderr, "%d: %v\n", puzzle.number, puzzle.err)
+				return exitPuzzle
+			}
+
+			sudokus = append(sudokus, puzzle.sudoku)
+		}
+
+		if *to != "pdf" && len(sudokus) != 1 {
+			fmt.Fprintln(cmd.stderr, "Sudoku: The format holds a single puzzle.")
+			return exitPuzzle
+		}
+
+		switch *to {
+		case "sdk":
+			err = sudokus[0].WriteSDK(cmd.stdout)
+		case "ss":
+			err = sudokus[0].WriteSS(cmd.stdout)
+		case "svg":
+			err = sudokus[0].RenderSVG(cmd.stdout, sudoku.SVGOptions{})
+		case "png":
+			err = sudokus[0].RenderPNG(cmd.stdout, sudoku.ImageOptions{})
+		case "pdf":
+			err = sudoku.WriteBooklet(cmd.stdout, sudokus, sudoku.BookletOptions{})
+		}
+
+		if err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			return exitPuzzle
+		}
+
+		return exitOK
+	}
+
+	binary := sudoku.NewBinaryWriter(cmd.stdout)
+
+	return cmd.each(func(puzzle cliPuzzle) error {
+		var text string
+		board := &puzzle.sudoku
+
+		switch *to {
+		case "line":
+			text = board.Line()
+		case "json":
+			data, err := json.Marshal(board)
+			if err != nil {
+				return err
+			}
+
+			text = string(data)
+		case "fpuzzles":
+			link, err := board.FPuzzlesURL()
+			if err != nil {
+				return err
+			}
+
+			text = link
+		case "binary":
+			return writeError(binary.Write(board))
+		case "text":
+			text = board.ToString()
+		case "ascii":
+			text = sudoku.Renderer{Mode: sudoku.ASCIIMode}.Render(board)
+		}
+
+		_, err := fmt.Fprintln(cmd.stdout, strings.TrimSuffix(text, "\n"))
+
+		return writeError(err)
+	})
+}
+
+func (cmd *cliCommand) batch(args []string) int {
+	job := cmd.flags.String("job", sudoku.BatchSolve, "solve or rate")
+	workers := cmd.flags.Int("workers", 0, "number of workers, one per CPU if 0")
+	progress := cmd.flags.Bool("progress", false, "report the progress on the standard error output")
+	cmd.flags.StringVar(&cmd.output, "output", "text", "format of the output: text or json")
+
+	if code, ok := cmd.parse(args, false); !ok {
+		return code
+	}
+
+	if (*job != sudoku.BatchSolve && *job != sudoku.BatchRate) || (cmd.output != "text" && cmd.output != "json") {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Unknown job or format.")
+		return exitUsage
+	}
+
+	input, closeInput, err := cmd.input()
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	defer closeInput()
+
+	opts := sudoku.BatchOptions{Job: *job, Workers: *workers, Output: cmd.output}
+
+	if *progress {
+		opts.Progress = func(progress sudoku.BatchProgress) {
+			fmt.Fprintf(cmd.stderr, "%d puzzles, %d errors, %.0f puzzles/s\n", progress.Done, progress.Errors, progress.Throughput())
+		}
+	}
+
+	result, err := sudoku.Batch(input, cmd.stdout, opts)
+
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	if result.Errors > 0 {
+		return exitPuzzle
+	}
+
+	return exitOK
+}
+
+func (cmd *cliCommand) serve(args []string) int {
+	addr := cmd.flags.String("addr", "localhost:8080", "address to listen on")
+	timeout := cmd.flags.Duration("timeout", 10*time.Second, "time a request may take")
+	play := cmd.flags.Bool("play", false, "serve multiplayer sessions over WebSockets on /play")
+	start := cmd.flags.String("daily-start", "2024-01-01", "first day of the archive of daily puzzles")
+
+	if code, ok := cmd.parse(args, false); !ok {
+		return code
+	}
+
+	opts := sudoku.ServerOptions{Timeout: *timeout}
+
+	var err error
+	if opts.DailyStart, err = time.Parse("2006-01-02", *start); err != nil {
+		fmt.Fprintln(cmd.stderr, "Sudoku: Invalid date.")
+		return exitUsage
+	}
+
+	if *play {
+		opts.Hub = sudoku.NewHub()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(cmd.stderr, "Listening on %s\n", *addr)
+
+	if err := sudoku.Serve(ctx, *addr, opts); err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	return exitOK
+}
+
+func (cmd *cliCommand) play(args []string) int {
+	difficulty := cmd.flags.String("difficulty", "easy", "difficulty of the generated puzzle when none is given")
+	limit := cmd.flags.Int("mistakes", 0, "number of mistakes which ends the game, 0 for no limit")
+	record := cmd.flags.String("record", "", "file where the log of the game is written, see replay")
+
+	if code, ok := cmd.parse(args, false); !ok {
+		return code
+	}
+
+	var puzzle sudoku.Sudoku
+	var err error
+
+	if line := cmd.flags.Arg(0); line != "" {
+		puzzle, err = sudoku.Parse(line)
+	} else {
+		puzzle, err = sudoku.NewGenerator(time.Now().UnixNano()).Generate(sudoku.ParseDifficulty(*difficulty), sudoku.Rotational)
+	}
+
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitPuzzle
+	}
+
+	game := sudoku.NewGame(puzzle)
+	game.SetMistakeLimit(*limit)
+
+	if *record != "" {
+		game.StartLog()
+	}
+
+	if err := sudoku.NewTUI(game).Run(cmd.stdin, cmd.stdout); err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	if *record != "" {
+		if err := game.MoveLog().SaveFile(*record); err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			return exitIO
+		}
+	}
+
+	return exitOK
+}
+
+// Returns the move log of the data, which holds a log written by "play
+// -record", or a game saved with its log, see @jsonGame.
+func parseMoveLog(data []byte) (*sudoku.MoveLog, error) {
+	var saved struct {
+		Sudoku json.RawMessage `json:"sudoku"`
+		Log    *sudoku.MoveLog `json:"log"`
+	}
+
+	if err := json.Unmarshal(data, &saved); err == nil && saved.Log != nil {
+		return saved.Log, nil
+	} else if err == nil && saved.Sudoku != nil {
+		return nil, errors.New("Sudoku: The saved game has no log.")
+	}
+
+	moves := &sudoku.MoveLog{}
+	if err := json.Unmarshal(data, moves); err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
+
+func (cmd *cliCommand) replay(args []string) int {
+	speed := cmd.flags.Float64("speed", 1, "how many times faster than the player to replay")
+	svg := cmd.flags.Bool("svg", false, "write the replay as an animated SVG image instead of playing it")
+
+	if code, ok := cmd.parse(args, false); !ok {
+		return code
+	}
+
+	input, closeInput, err := cmd.input()
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	data, err := io.ReadAll(input)
+	closeInput()
+
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	moves, err := parseMoveLog(data)
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitPuzzle
+	}
+
+	replayer := sudoku.NewReplayer(moves, *speed)
+
+	if *svg {
+		err = replayer.ExportSVG(cmd.stdout, sudoku.SVGOptions{})
+	} else {
+		err = replayer.Play(cmd.stdout)
+	}
+
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitIO
+	}
+
+	return exitOK
+}
+
+// Runs the command-line tool with the given arguments, without the name of the
+// program, and returns its exit code, see @exitOK.
+func runCLI(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, cliUsage)
+		return exitUsage
+	}
+
+	cmd := &cliCommand{
+		flags:  flag.NewFlagSet(args[0], flag.ContinueOnError),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		format: "line",
+		output: "text",
+	}
+
+	cmd.flags.SetOutput(stderr)
+
+	commands := map[string]func(args []string) int{
+		"play":     cmd.play,
+		"solve":    cmd.solve,
+		"generate": cmd.generate,
+		"rate":     cmd.rate,
+		"validate": cmd.validate,
+		"convert":  cmd.convert,
+		"batch":    cmd.batch,
+		"serve":    cmd.serve,
+		"replay":   cmd.replay,
+	}
+
+	run, ok := commands[args[0]]
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stdout, cliUsage)
+		return exitOK
+	}
+
+	if !ok {
+		fmt.Fprintf(stderr, "Sudoku: Unknown command %q.\n\n%s", args[0], cliUsage)
+		return exitUsage
+	}
+
+	return run(args[1:])
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maucarrui/Sudoku2Go/sudoku"
+)
+
+// Runs the command-line tool with the input and returns its exit code and
+// outputs.
+func runTool(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI(args, strings.NewReader(input), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+// A clock which only moves when told to.
+type stepClock struct {
+	now time.Time
+}
+
+func (clock *stepClock) Now() time.Time {
+	return clock.now
+}
+
+func TestCLI(t *testing.T) {
+	puzzle := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	solution := "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+
+	if code, out, _ := runTool(puzzle+"\n", "solve"); code != exitOK || out != solution+"\n" {
+		t.Errorf("Sudoku: solve exited with %d and printed %q", code, out)
+	}
+
+	// A bad line doesn't stop the other puzzles.
+	code, out, errs := runTool("# Puzzles\n123\n"+puzzle+"\n", "solve", "-output", "json")
+	var result solveResult
+
+	if code != exitPuzzle || !strings.HasPrefix(errs, "2: ") || json.Unmarshal([]byte(out), &result) != nil || result.Solution != solution || !result.Unique {
+		t.Errorf("Sudoku: solve exited with %d, printed %q and reported %q", code, out, errs)
+	}
+
+	if code, out, _ := runTool(puzzle, "rate"); code != exitOK || !strings.HasPrefix(out, "Easy ") {
+		t.Errorf("Sudoku: rate exited with %d and printed %q", code, out)
+	}
+
+	if code, out, _ := runTool(puzzle, "validate"); code != exitOK || out != "valid: not minimal\n" {
+		t.Errorf("Sudoku: validate exited with %d and printed %q", code, out)
+	}
+
+	if code, _, _ := runTool(puzzle, "validate", "-minimal"); code != exitPuzzle {
+		t.Errorf("Sudoku: validate -minimal exited with %d", code)
+	}
+
+	if code, out, _ := runTool("", "generate", "-count", "2", "-seed", "3", "-difficulty", "medium"); code != exitOK || strings.Count(out, "\n") != 2 {
+		t.Errorf("Sudoku: generate exited with %d and printed %q", code, out)
+	}
+
+	// Converting to JSON and back gives the same puzzle.
+	_, data, _ := runTool(puzzle, "convert", "-to", "json")
+
+	if code, out, _ := runTool(data, "convert", "-format", "json", "-to", "line"); code != exitOK || strings.ReplaceAll(out, ".", "0") != puzzle+"\n" {
+		t.Errorf("Sudoku: convert exited with %d and printed %q", code, out)
+	}
+
+	if code, out, _ := runTool(puzzle, "convert", "-to", "sdk"); code != exitOK || !strings.HasPrefix(out, "53..7....\n") {
+		t.Errorf("Sudoku: convert to sdk exited with %d and printed %q", code, out)
+	}
+
+	if code, out, _ := runTool(puzzle+"\n123\n", "batch", "-job", "rate", "-workers", "2"); code != exitPuzzle || !strings.HasPrefix(out, "Easy ") {
+		t.Errorf("Sudoku: batch exited with %d and printed %q", code, out)
+	}
+
+	for _, args := range [][]string{{}, {"unknown"}, {"solve", "-format", "unknown"}, {"solve", "-bad"}, {"convert", "-to", "unknown"},
+		{"generate", "-difficulty", "unknown"}, {"generate", "-symmetry", "unknown"},
+		{"generate", "-output", "unknown"}, {"serve", "-daily-start", "yesterday"}} {
+		if code, _, _ := runTool(puzzle, args...); code != exitUsage {
+			t.Errorf("Sudoku: %v exited with %d", args, code)
+		}
+	}
+
+	if code, _, _ := runTool("", "solve", "missing-file.txt"); code != exitIO {
+		t.Errorf("Sudoku: Missing file exited with %d", code)
+	}
+}
+
+func TestCLIReplay(t *testing.T) {
+	puzzle, _ := sudoku.Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	givens, _ := json.Marshal(puzzle)
+	log := `{"version": 1, "puzzle": ` + string(givens) + `, "actions": "1000:p024 1500:t036"}`
+
+	if code, out, errs := runTool(log, "replay", "-svg"); code != exitOK || strings.Count(out, "<animate ") != 3 {
+		t.Errorf("Sudoku: replay -svg exited with %d: %s", code, errs)
+	}
+
+	// A saved game carries its log, with the same actions as the one above.
+	clock := &stepClock{time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	game := sudoku.NewGame(puzzle)
+	game.SetClock(clock)
+	game.StartLog()
+
+	clock.now = clock.now.Add(time.Second)
+	game.SetValue(0, 2, 4)
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	game.ToggleCandidate(0, 3, 6)
+	data, _ := json.Marshal(game)
+
+	if code, out, errs := runTool(string(data), "replay", "-svg"); code != exitOK || strings.Count(out, "<animate ") != 3 {
+		t.Errorf("Sudoku: replay -svg of a saved game exited with %d and printed %q: %s", code, out, errs)
+	}
+
+	// Games saved without a log can't be replayed.
+	data, _ = json.Marshal(sudoku.NewGame(puzzle))
+
+	if code, _, errs := runTool(string(data), "replay"); code != exitPuzzle || !strings.Contains(errs, "no log") {
+		t.Errorf("Sudoku: replay of a game without log exited with %d: %s", code, errs)
+	}
+
+	if code, _, _ := runTool("{}", "replay"); code != exitPuzzle {
+		t.Errorf("Sudoku: replay of an invalid log should exit with %d but exited with %d", exitPuzzle, code)
+	}
+}
@@ -0,0 +1,328 @@
+package sudoku
+
+import (
+	"bufio"         // Reading lines.
+	"encoding/json" // JSON records.
+	"errors"        // Error handling.
+	"fmt"           // String formatting.
+	"io"            // Readers and writers.
+	"runtime"       // Number of CPUs.
+	"strings"       // String manipulation.
+	"sync"          // Waiting for the workers.
+	"time"          // Progress reports.
+)
+
+// The jobs of @Batch.
+const (
+	// Writes the first solution of every puzzle.
+	BatchSolve = "solve"
+
+	// Writes the difficulty and score of every puzzle, see @Rate.
+	BatchRate = "rate"
+)
+
+// The number of puzzles each worker may be ahead of the output, which bounds
+// the memory used to keep the input order.
+const batchWindow = 256
+
+// The longest line read by @Batch. Longer lines can't be a puzzle, so they are
+// skipped with an error record instead of being kept in memory.
+const batchMaxLine = 64 * 1024
+
+// BatchOptions configures @Batch. The zero value solves the puzzles with one
+// worker per CPU and writes text records.
+type BatchOptions struct {
+	// BatchSolve or BatchRate, BatchSolve if empty.
+	Job string
+
+	// The number of goroutines working on the puzzles, the number of CPUs if
+	// zero.
+	Workers int
+
+	// "text" or "json", "text" if empty. See @Batch for the records.
+	Output string
+
+	// Called every ProgressInterval, 1 second if zero, and once more at the
+	// end. Called from the goroutine of @Batch.
+	Progress         func(progress BatchProgress)
+	ProgressInterval time.Duration
+}
+
+// BatchProgress tells how far a call to @Batch got.
+type BatchProgress struct {
+	// The number of puzzles written so far, and how many of them failed.
+	Done   int
+	Errors int
+
+	// The time since the batch started.
+	Elapsed time.Duration
+}
+
+// Returns the number of puzzles written per second.
+func (progress BatchProgress) Throughput() float64 {
+	if progress.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(progress.Done) / progress.Elapsed.Seconds()
+}
+
+// A line of the input of @Batch, with its position among the puzzles and on
+// the input, and the error found while reading it if any.
+type batchJob struct {
+	index int
+	line  int
+	text  string
+	err   error
+}
+
+// The record written for a job.
+type batchResult struct {
+	index  int
+	record string
+	failed bool
+}
+
+// The JSON record of a puzzle written by @Batch.
+type batchRecord struct {
+	Line       int    `json:"line"`
+	Solution   string `json:"solution,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Score      int    `json:"score,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Returns the record of the puzzle on the line, and true if it failed.
+func (opts *BatchOptions) process(job batchJob) (string, bool) {
+	record := batchRecord{Line: job.line}
+	text := ""
+
+	sudoku, err := Parse(job.text)
+	if job.err != nil {
+		err = job.err
+	}
+
+	if err == nil && opts.Job == BatchRate {
+		var rating Rating
+
+		if rating, err = sudoku.Rate(); err == nil {
+			record.Difficulty, record.Score = rating.Difficulty, rating.Score
+			text = fmt.Sprintf("%s %d", rating.Difficulty, rating.Score)
+		}
+	} else if err == nil {
+		var solved Sudoku
+
+		if solved, err = sudoku.Solve(); err == nil {
+			record.Solution = gridString(solved.values)
+			text = record.Solution
+		}
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+		text = fmt.Sprintf("error: line %d: %v", job.line, err)
+	}
+
+	if opts.Output == "json" {
+		data, _ := json.Marshal(record)
+		text = string(data)
+	}
+
+	return text, err != nil
+}
+
+// Returns the next line of the reader, with its line end if any. Lines longer
+// than batchMaxLine are skipped: nothing is returned for them and the flag is
+// true. The error is io.EOF once there are no more lines.
+func readBatchLine(reader *bufio.Reader) (string, bool, error) {
+	var data []byte
+	tooLong := false
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+
+		if tooLong || len(data)+len(chunk) > batchMaxLine {
+			tooLong, data = true, nil
+		} else {
+			data = append(data, chunk...)
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(data) > 0 || tooLong):
+			return string(data), tooLong, nil
+		default:
+			return string(data), tooLong, err
+		}
+	}
+}
+
+// Batch solves or rates every puzzle of r, one per line in one of the formats
+// of @Parse, and writes one record per puzzle to w in the order of the input.
+// Empty lines and lines starting with '#' are skipped. The puzzles are spread
+// across a pool of workers, so the output of a puzzle may wait for slower
+// puzzles before it.
+//
+// Text records are the solution in the 81 character format, or the
+// difficulty and the score separated by a space. JSON records are objects on
+// a line of their own, see @batchRecord. A puzzle which can't be read, solved
+// or rated, or a line longer than 64 KiB, doesn't stop the batch: its record
+// is "error: line N: ..." or a JSON record with an "error" field.
+//
+// Returns the final progress, and an error if the input can't be read or the
+// output can't be written.
+func Batch(r io.Reader, w io.Writer, opts BatchOptions) (BatchProgress, error) {
+	var progress BatchProgress
+
+	if opts.Job == "" {
+		opts.Job = BatchSolve
+	}
+
+	if opts.Output == "" {
+		opts.Output = "text"
+	}
+
+	if opts.Job != BatchSolve && opts.Job != BatchRate {
+		return progress, errors.New("Sudoku: Unknown batch job.")
+	}
+
+	if opts.Output != "text" && opts.Output != "json" {
+		return progress, errors.New("Sudoku: Unknown format.")
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = time.Second
+	}
+
+	start := time.Now()
+	jobs := make(chan batchJob, opts.Workers)
+	results := make(chan batchResult, opts.Workers)
+
+	// A token is taken for every job read and given back once its record is
+	// written, so the reader can't get too far ahead of the writer.
+	tokens := make(chan struct{}, opts.Workers*batchWindow)
+
+	// Closed when the writer stops early, so the reader stops as well.
+	done := make(chan struct{})
+
+	var readErr error
+
+	go func() {
+		defer close(jobs)
+
+		reader := bufio.NewReader(r)
+		index := 0
+
+		for line := 1; ; line++ {
+			text, tooLong, err := readBatchLine(reader)
+			if err != nil {
+				if err != io.EOF {
+					readErr = err
+				}
+
+				return
+			}
+
+			var jobErr error
+			text = strings.TrimSpace(text)
+
+			if tooLong {
+				text, jobErr = "", errors.New("Sudoku: Line is too long.")
+			} else if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+
+			jobs <- batchJob{index, line, text, jobErr}
+			index++
+		}
+	}()
+
+	var workers sync.WaitGroup
+
+	for k := 0; k < opts.Workers; k++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for job := range jobs {
+				record, failed := opts.process(job)
+				results <- batchResult{job.index, record, failed}
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	out := bufio.NewWriter(w)
+	pending := map[int]batchResult{}
+	lastReport := start
+	var writeErr error
+
+	for result := range results {
+		if writeErr != nil {
+			<-tokens
+			continue
+		}
+
+		pending[result.index] = result
+
+		for {
+			next, ok := pending[progress.Done]
+			if !ok {
+				break
+			}
+
+			delete(pending, progress.Done)
+			<-tokens
+
+			if _, err := out.WriteString(next.record + "\n"); err != nil {
+				writeErr = err
+				close(done)
+				break
+			}
+
+			progress.Done++
+
+			if next.failed {
+				progress.Errors++
+			}
+		}
+
+		if opts.Progress != nil && time.Since(lastReport) >= opts.ProgressInterval {
+			lastReport = time.Now()
+			progress.Elapsed = lastReport.Sub(start)
+			opts.Progress(progress)
+		}
+	}
+
+	if writeErr == nil {
+		writeErr = out.Flush()
+	}
+
+	progress.Elapsed = time.Since(start)
+
+	if opts.Progress != nil {
+		opts.Progress(progress)
+	}
+
+	if writeErr != nil {
+		return progress, writeErr
+	}
+
+	return progress, readErr
+}
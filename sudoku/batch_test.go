@@ -0,0 +1,90 @@
+package sudoku
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// A writer which always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestBatch(t *testing.T) {
+	puzzles := []string{
+		"530070000600195000098000060800060003400803001700020006060000280000419005000080079",
+		"800000000003600000070090200050007000000045700000100030001000068008500010090000400",
+		"400000938032094100095300240370609004529001673604703090957008300003900400240030709",
+	}
+
+	var input strings.Builder
+	var expected []string
+
+	// Many puzzles so the workers finish out of order.
+	for k := 0; k < 60; k++ {
+		puzzle := puzzles[k%len(puzzles)]
+
+		sudoku, _ := Parse(puzzle)
+		solved, _ := sudoku.Solve()
+
+		input.WriteString(puzzle + "\n")
+		expected = append(expected, gridString(solved.values))
+	}
+
+	input.WriteString("\n# A bad line.\n123\n")
+	expected = append(expected, "error: line 63: ")
+
+	// Lines too long for a puzzle are an error as well, not the end of the
+	// input.
+	input.WriteString(strings.Repeat("1", batchMaxLine+10) + "\n" + puzzles[0])
+	expected = append(expected, "error: line 64: Sudoku: Line is too long.", expected[0])
+
+	var out bytes.Buffer
+	reports := 0
+
+	progress, err := Batch(strings.NewReader(input.String()), &out, BatchOptions{
+		Workers:  4,
+		Progress: func(BatchProgress) { reports++ },
+	})
+
+	if err != nil || progress.Done != 63 || progress.Errors != 2 || reports == 0 {
+		t.Errorf("Sudoku: Batch ended with %+v after %d reports (%v)", progress, reports, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Sudoku: Batch wrote %d records instead of %d", len(lines), len(expected))
+	}
+
+	for k := range expected {
+		if !strings.HasPrefix(lines[k], expected[k]) {
+			t.Errorf("Sudoku: Record %d should be %q but is %q", k, expected[k], lines[k])
+		}
+	}
+
+	// JSON records of the ratings.
+	out.Reset()
+	Batch(strings.NewReader(puzzles[0]+"\n123\n"), &out, BatchOptions{Job: BatchRate, Output: "json"})
+
+	decoder := json.NewDecoder(&out)
+	var first, second batchRecord
+
+	if decoder.Decode(&first) != nil || decoder.Decode(&second) != nil ||
+		first.Difficulty != Easy || first.Line != 1 || second.Error == "" || second.Line != 2 {
+		t.Errorf("Sudoku: Unexpected JSON records %+v and %+v", first, second)
+	}
+
+	if _, err := Batch(strings.NewReader(input.String()), failingWriter{}, BatchOptions{}); err == nil {
+		t.Errorf("Sudoku: Batch didn't report the output error.")
+	}
+
+	if _, err := Batch(strings.NewReader(""), &out, BatchOptions{Job: "count"}); err == nil {
+		t.Errorf("Sudoku: Batch accepted an unknown job.")
+	}
+}
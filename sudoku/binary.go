@@ -0,0 +1,144 @@
+package sudoku
+
+import (
+	"errors" // Error handling.
+	"io"     // Readers and writers.
+)
+
+// The size in bytes of a sudoku encoded by @MarshalBinary.
+const BinarySize = 45
+
+// MarshalBinary encodes the givens and values of the sudoku in @BinarySize
+// bytes. When the values hold the solution of the puzzle, the record contains
+// both the puzzle and its solution.
+//
+// The first 11 bytes are a bitmask of the cells holding a given, where the bit
+// (k % 8) of the byte (k / 8) belongs to the cell k = 9x + y. The next 34 bytes
+// pack the values, 0 for an empty cell, three cells at a time as the number
+// 100a + 10b + c, which fits in 10 bits. Candidates and metadata are not
+// encoded.
+func (sudoku Sudoku) MarshalBinary() ([]byte, error) {
+	data := make([]byte, BinarySize)
+
+	for k := 0; k < 81; k++ {
+		if sudoku.initialValues[k/9][k%9] != 0 {
+			data[k/8] |= 1 << (k % 8)
+		}
+	}
+
+	bit := 11 * 8
+
+	for k := 0; k < 81; k += 3 {
+		group := 0
+
+		for i := k; i < k+3; i++ {
+			group = group*10 + sudoku.values[i/9][i%9]
+		}
+
+		for i := 0; i < 10; i++ {
+			if group&(1<<i) != 0 {
+				data[bit/8] |= 1 << (bit % 8)
+			}
+
+			bit++
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sudoku written by @MarshalBinary.
+func (sudoku *Sudoku) UnmarshalBinary(data []byte) error {
+	var result Sudoku
+
+	if len(data) != BinarySize {
+		return errors.New("Sudoku: Invalid binary size.")
+	}
+
+	bit := 11 * 8
+
+	for k := 0; k < 81; k += 3 {
+		group := 0
+
+		for i := 0; i < 10; i++ {
+			if data[bit/8]&(1<<(bit%8)) != 0 {
+				group |= 1 << i
+			}
+
+			bit++
+		}
+
+		if group > 999 {
+			return errors.New("Sudoku: Invalid binary values.")
+		}
+
+		for i := k + 2; i >= k; i-- {
+			val := group % 10
+			group /= 10
+
+			given := data[i/8]&(1<<(i%8)) != 0
+
+			if given && val == 0 {
+				return errors.New("Sudoku: Invalid binary values.")
+			}
+
+			if given {
+				result.SetInitialValue(i/9, i%9, val)
+			} else if val != 0 {
+				result.SetValue(i/9, i%9, val)
+			}
+		}
+	}
+
+	*sudoku = result
+
+	return nil
+}
+
+// BinaryReader reads a stream of concatenated sudokus encoded with
+// @MarshalBinary.
+type BinaryReader struct {
+	r   io.Reader
+	buf [BinarySize]byte
+}
+
+// Returns a new reader of binary records from r. Reads are not buffered, so r
+// should be buffered for large streams.
+func NewBinaryReader(r io.Reader) *BinaryReader {
+	return &BinaryReader{r: r}
+}
+
+// Reads the next sudoku of the stream. Returns io.EOF when there are no more
+// records, and io.ErrUnexpectedEOF if the last record is incomplete.
+func (reader *BinaryReader) Read() (Sudoku, error) {
+	var sudoku Sudoku
+
+	if _, err := io.ReadFull(reader.r, reader.buf[:]); err != nil {
+		return Sudoku{}, err
+	}
+
+	err := sudoku.UnmarshalBinary(reader.buf[:])
+
+	return sudoku, err
+}
+
+// BinaryWriter writes a stream of concatenated sudokus encoded with
+// @MarshalBinary.
+type BinaryWriter struct {
+	w io.Writer
+}
+
+// Returns a new writer of binary records to w. Writes are not buffered, so w
+// should be buffered for large streams.
+func NewBinaryWriter(w io.Writer) *BinaryWriter {
+	return &BinaryWriter{w: w}
+}
+
+// Writes the sudoku as the next record of the stream.
+func (writer *BinaryWriter) Write(sudoku *Sudoku) error {
+	data, _ := sudoku.MarshalBinary()
+
+	_, err := writer.w.Write(data)
+
+	return err
+}
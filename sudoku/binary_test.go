@@ -0,0 +1,75 @@
+package sudoku
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	solution := "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+
+	for k, r := range solution {
+		sudoku.SetValue(k/9, k%9, int(r-'0'))
+	}
+
+	data, _ := sudoku.MarshalBinary()
+
+	if len(data) != BinarySize {
+		t.Errorf("Sudoku: Encoded size should be %d but is %d", BinarySize, len(data))
+	}
+
+	var other Sudoku
+	if err := other.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Sudoku: Can't decode binary: %v", err)
+	}
+
+	if other.values != sudoku.values || other.initialValues != sudoku.initialValues {
+		t.Errorf("Sudoku: Decoded\n%v\ninstead of\n%v", other.ToString(), sudoku.ToString())
+	}
+
+	// Invalid records must be rejected.
+	if err := other.UnmarshalBinary(data[:10]); err == nil {
+		t.Errorf("Sudoku: Accepts short binary.")
+	}
+
+	var empty Sudoku
+	data, _ = empty.MarshalBinary()
+	data[0] = 1
+
+	if err := other.UnmarshalBinary(data); err == nil {
+		t.Errorf("Sudoku: Accepts an empty given.")
+	}
+}
+
+func TestBinaryStream(t *testing.T) {
+	var b bytes.Buffer
+	var sudokus [3]Sudoku
+
+	sudokus[0], _ = Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	sudokus[2].SetValue(4, 4, 9)
+
+	writer := NewBinaryWriter(&b)
+	for i := range sudokus {
+		writer.Write(&sudokus[i])
+	}
+
+	if b.Len() != 3*BinarySize {
+		t.Errorf("Sudoku: Stream size should be %d but is %d", 3*BinarySize, b.Len())
+	}
+
+	reader := NewBinaryReader(&b)
+	for i := range sudokus {
+		sudoku, err := reader.Read()
+
+		if err != nil || !reflect.DeepEqual(sudoku, sudokus[i]) {
+			t.Errorf("Sudoku: Record %d was read as\n%v", i, sudoku.ToString())
+		}
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("Sudoku: Expected end of stream but got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package sudoku
+
+// The units of a sudoku, every one of them must hold the values 1 to 9.
+const (
+	RowUnit    = "row"
+	ColumnUnit = "column"
+	BlockUnit  = "block"
+)
+
+// Conflict is a pair of cells of the same unit holding the same value.
+type Conflict struct {
+	// RowUnit, ColumnUnit or BlockUnit.
+	Unit string `json:"unit"`
+
+	// The index of the row, column or block, see @GetBlock for the enumeration
+	// of blocks.
+	Index int `json:"index"`
+
+	// The cells in conflict, A comes before B from left to right and from top
+	// to bottom.
+	A Cell `json:"a"`
+	B Cell `json:"b"`
+
+	Value int `json:"value"`
+}
+
+// Returns the cells of the unit with the given index.
+func unitCells(unit string, index int) [9]Cell {
+	var cells [9]Cell
+
+	for k := 0; k < 9; k++ {
+		switch unit {
+		case RowUnit:
+			cells[k] = Cell{index, k}
+		case ColumnUnit:
+			cells[k] = Cell{k, index}
+		case BlockUnit:
+			cells[k] = Cell{(index/3)*3 + k/3, (index%3)*3 + k%3}
+		}
+	}
+
+	return cells
+}
+
+// Returns every pair of cells of the unit which hold the same value. Empty
+// cells are ignored.
+func (sudoku *Sudoku) unitConflicts(unit string, index int) []Conflict {
+	var conflicts []Conflict
+
+	cells := unitCells(unit, index)
+
+	for a := 0; a < 9; a++ {
+		val := sudoku.values[cells[a].Row][cells[a].Column]
+
+		if val == 0 {
+			continue
+		}
+
+		for b := a + 1; b < 9; b++ {
+			if sudoku.values[cells[b].Row][cells[b].Column] == val {
+				conflicts = append(conflicts, Conflict{unit, index, cells[a], cells[b], val})
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// Returns every pair of cells holding the same value in a row, column or
+// block. Empty cells are never in conflict, so an unfinished sudoku without
+// mistakes has no conflicts. The conflicts of all rows come first, then the
+// ones of the columns and then the ones of the blocks.
+func (sudoku *Sudoku) Conflicts() []Conflict {
+	var conflicts []Conflict
+
+	for _, unit := range []string{RowUnit, ColumnUnit, BlockUnit} {
+		for index := 0; index < 9; index++ {
+			conflicts = append(conflicts, sudoku.unitConflicts(unit, index)...)
+		}
+	}
+
+	return conflicts
+}
+
+// Returns true if a value is repeated in the row x. Unlike @IsValidRow, empty
+// cells are ignored.
+func (sudoku *Sudoku) HasDuplicatesInRow(x int) bool {
+	return len(sudoku.unitConflicts(RowUnit, x)) > 0
+}
+
+// Returns true if a value is repeated in the column y. Unlike @IsValidColumn,
+// empty cells are ignored.
+func (sudoku *Sudoku) HasDuplicatesInColumn(y int) bool {
+	return len(sudoku.unitConflicts(ColumnUnit, y)) > 0
+}
+
+// Returns true if a value is repeated in the block z. Unlike @IsValidBlock,
+// empty cells are ignored.
+func (sudoku *Sudoku) HasDuplicatesInBlock(z int) bool {
+	return len(sudoku.unitConflicts(BlockUnit, z)) > 0
+}
+
+// Returns which cells are part of a conflict.
+func (sudoku *Sudoku) conflictCells() [9][9]bool {
+	var cells [9][9]bool
+
+	for _, conflict := range sudoku.Conflicts() {
+		cells[conflict.A.Row][conflict.A.Column] = true
+		cells[conflict.B.Row][conflict.B.Column] = true
+	}
+
+	return cells
+}
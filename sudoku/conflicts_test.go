@@ -0,0 +1,43 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConflicts(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	// The puzzle has empty cells but no mistakes.
+	if conflicts := sudoku.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("Sudoku: Puzzle without mistakes has conflicts: %v", conflicts)
+	}
+
+	for i := 0; i < 9; i++ {
+		if sudoku.HasDuplicatesInRow(i) || sudoku.HasDuplicatesInColumn(i) || sudoku.HasDuplicatesInBlock(i) {
+			t.Errorf("Sudoku: Unit %d has duplicates.", i)
+		}
+	}
+
+	// A 5 on (1, 1) repeats the 5 of its block on (0, 0) and the 5 of its row
+	// on (1, 5).
+	sudoku.SetValue(1, 1, 5)
+
+	expected := []Conflict{
+		{RowUnit, 1, Cell{1, 1}, Cell{1, 5}, 5},
+		{BlockUnit, 0, Cell{0, 0}, Cell{1, 1}, 5},
+	}
+
+	if conflicts := sudoku.Conflicts(); !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("Sudoku: Conflicts should be %v but are %v", expected, conflicts)
+	}
+
+	if !sudoku.HasDuplicatesInRow(1) || sudoku.HasDuplicatesInColumn(1) || !sudoku.HasDuplicatesInBlock(0) {
+		t.Errorf("Sudoku: Duplicates were not found.")
+	}
+
+	cells := sudoku.conflictCells()
+	if !cells[0][0] || !cells[1][1] || !cells[1][5] || cells[0][1] {
+		t.Errorf("Sudoku: Wrong cells in conflict: %v", cells)
+	}
+}
@@ -0,0 +1,219 @@
+package sudoku
+
+import (
+	"errors" // Error handling.
+	"fmt"    // String formatting.
+)
+
+// Cell identifies the cell on the row Row and column Column of a sudoku.
+type Cell struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+// Returns the cell as "r1c1", with rows and columns starting at 1.
+func (cell Cell) String() string {
+	return fmt.Sprintf("r%dc%d", cell.Row+1, cell.Column+1)
+}
+
+// The types of variant constraints.
+const (
+	// The values of the cells add up to Value and can't repeat.
+	Cage = "cage"
+
+	// The values strictly increase from the first cell to the last one.
+	Thermometer = "thermometer"
+
+	// The value of the first cell is the sum of the values of the others.
+	Arrow = "arrow"
+
+	// The values read the same from both ends.
+	Palindrome = "palindrome"
+
+	// The two cells differ by Value, 1 if not given.
+	WhiteDot = "white-dot"
+
+	// One of the two cells is Value times the other one, 2 if not given.
+	BlackDot = "black-dot"
+
+	// The values of the nine cells can't repeat, like in a block.
+	Region = "region"
+)
+
+// Constraint is a variant rule which applies to some cells of the sudoku, on
+// top of the usual row, column and block rules.
+type Constraint struct {
+	Type  string `json:"type"`
+	Cells []Cell `json:"cells"`
+	Value int    `json:"value,omitempty"`
+}
+
+// Adds a variant constraint to the sudoku. Returns an error if the type is
+// unknown or the cells are not valid for the constraint.
+func (sudoku *Sudoku) AddConstraint(constraint Constraint) error {
+	for _, cell := range constraint.Cells {
+		if cell.Row < 0 || cell.Row > 8 {
+			return errors.New("Sudoku: Invalid row.")
+		}
+
+		if cell.Column < 0 || cell.Column > 8 {
+			return errors.New("Sudoku: Invalid column.")
+		}
+	}
+
+	n := len(constraint.Cells)
+
+	switch constraint.Type {
+	case Cage, Thermometer, Palindrome:
+		if n < 1 {
+			return errors.New("Sudoku: Constraint without cells.")
+		}
+	case Arrow:
+		if n < 2 {
+			return errors.New("Sudoku: Arrow needs a circle and at least one cell.")
+		}
+	case WhiteDot, BlackDot:
+		if n != 2 {
+			return errors.New("Sudoku: Dot must be between two cells.")
+		}
+	case Region:
+		if n != 9 {
+			return errors.New("Sudoku: Region must have nine cells.")
+		}
+	default:
+		return errors.New("Sudoku: Unknown constraint type.")
+	}
+
+	constraint.Cells = append([]Cell(nil), constraint.Cells...)
+	sudoku.constraints = append(sudoku.constraints, constraint)
+	sudoku.forgetSolution()
+
+	return nil
+}
+
+// Returns the variant constraints of the sudoku.
+func (sudoku *Sudoku) GetConstraints() []Constraint {
+	return append([]Constraint(nil), sudoku.constraints...)
+}
+
+// Returns the values of @possibleMask for the cell which don't break any of
+// the constraints on it either.
+func (sudoku *Sudoku) allowedMask(x, y int) uint16 {
+	mask := sudoku.possibleMask(x, y)
+	scratch := Sudoku{values: sudoku.values}
+
+	for k := range sudoku.constraints {
+		constraint := &sudoku.constraints[k]
+
+		on := false
+
+		for _, cell := range constraint.Cells {
+			on = on || cell == Cell{x, y}
+		}
+
+		if !on {
+			continue
+		}
+
+		for _, val := range maskDigits(mask) {
+			scratch.values[x][y] = val
+
+			if constraint.Violated(&scratch) {
+				mask &^= 1 << val
+			}
+		}
+	}
+
+	return mask
+}
+
+// Returns true if the filled cells already break the constraint. Empty cells
+// never break it, so a partially filled sudoku can be checked as well.
+func (constraint *Constraint) Violated(sudoku *Sudoku) bool {
+	vals := make([]int, len(constraint.Cells))
+	full := true
+
+	for i, cell := range constraint.Cells {
+		vals[i] = sudoku.values[cell.Row][cell.Column]
+
+		if vals[i] == 0 {
+			full = false
+		}
+	}
+
+	switch constraint.Type {
+	case Cage, Region:
+		var seen [10]bool
+		sum := 0
+
+		for _, val := range vals {
+			if val != 0 && seen[val] {
+				return true
+			}
+
+			seen[val] = true
+			sum += val
+		}
+
+		if constraint.Type == Cage && constraint.Value > 0 {
+			return sum > constraint.Value || (full && sum != constraint.Value)
+		}
+
+	case Thermometer:
+		// Every filled cell must leave enough room for the cells between it
+		// and the previous filled one.
+		last, lastIndex := 0, -1
+
+		for i, val := range vals {
+			if val == 0 {
+				continue
+			}
+
+			if val-last < i-lastIndex || val > 9-(len(vals)-1-i) {
+				return true
+			}
+
+			last, lastIndex = val, i
+		}
+
+	case Arrow:
+		sum := 0
+
+		for _, val := range vals[1:] {
+			sum += val
+		}
+
+		if vals[0] != 0 && (sum > vals[0] || (full && sum != vals[0])) {
+			return true
+		}
+
+	case Palindrome:
+		for i, j := 0, len(vals)-1; i < j; i, j = i+1, j-1 {
+			if vals[i] != 0 && vals[j] != 0 && vals[i] != vals[j] {
+				return true
+			}
+		}
+
+	case WhiteDot:
+		diff := constraint.Value
+		if diff == 0 {
+			diff = 1
+		}
+
+		if full && vals[0]-vals[1] != diff && vals[1]-vals[0] != diff {
+			return true
+		}
+
+	case BlackDot:
+		ratio := constraint.Value
+		if ratio == 0 {
+			ratio = 2
+		}
+
+		if full && vals[0] != ratio*vals[1] && vals[1] != ratio*vals[0] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+func TestAddConstraint(t *testing.T) {
+	var sudoku Sudoku
+
+	invalid := []Constraint{
+		{"sandwich", []Cell{{0, 0}}, 0},
+		{Cage, []Cell{{0, 9}}, 0},
+		{Cage, nil, 3},
+		{Arrow, []Cell{{0, 0}}, 0},
+		{WhiteDot, []Cell{{0, 0}, {0, 1}, {0, 2}}, 0},
+		{Region, []Cell{{0, 0}}, 0},
+	}
+
+	for _, constraint := range invalid {
+		if err := sudoku.AddConstraint(constraint); err == nil {
+			t.Errorf("Sudoku: Accepts invalid constraint %v", constraint)
+		}
+	}
+
+	if len(sudoku.GetConstraints()) != 0 {
+		t.Errorf("Sudoku: Invalid constraints were added: %v", sudoku.GetConstraints())
+	}
+}
+
+func TestViolated(t *testing.T) {
+	tests := []struct {
+		constraint Constraint
+		values     []int
+		violated   bool
+	}{
+		{Constraint{Cage, []Cell{{0, 0}, {0, 1}, {1, 0}}, 10}, []int{1, 2, 0}, false},
+		{Constraint{Cage, []Cell{{0, 0}, {0, 1}, {1, 0}}, 10}, []int{1, 2, 7}, false},
+		{Constraint{Cage, []Cell{{0, 0}, {0, 1}, {1, 0}}, 10}, []int{1, 2, 6}, true},
+		{Constraint{Cage, []Cell{{0, 0}, {0, 1}, {1, 0}}, 10}, []int{9, 2, 0}, true},
+		{Constraint{Cage, []Cell{{0, 0}, {1, 1}}, 0}, []int{4, 4}, true},
+		{Constraint{Thermometer, []Cell{{0, 0}, {0, 1}, {0, 2}}, 0}, []int{1, 0, 3}, false},
+		{Constraint{Thermometer, []Cell{{0, 0}, {0, 1}, {0, 2}}, 0}, []int{2, 0, 3}, true},
+		{Constraint{Thermometer, []Cell{{0, 0}, {0, 1}, {0, 2}}, 0}, []int{0, 9, 0}, true},
+		{Constraint{Thermometer, []Cell{{0, 0}, {0, 1}, {0, 2}}, 0}, []int{3, 0, 0}, false},
+		{Constraint{Arrow, []Cell{{0, 0}, {1, 1}, {2, 2}}, 0}, []int{9, 4, 5}, false},
+		{Constraint{Arrow, []Cell{{0, 0}, {1, 1}, {2, 2}}, 0}, []int{9, 4, 4}, true},
+		{Constraint{Arrow, []Cell{{0, 0}, {1, 1}, {2, 2}}, 0}, []int{3, 4, 0}, true},
+		{Constraint{Palindrome, []Cell{{0, 0}, {1, 1}, {2, 2}}, 0}, []int{3, 4, 0}, false},
+		{Constraint{Palindrome, []Cell{{0, 0}, {1, 1}, {2, 2}}, 0}, []int{3, 4, 2}, true},
+		{Constraint{WhiteDot, []Cell{{0, 0}, {0, 1}}, 0}, []int{5, 4}, false},
+		{Constraint{WhiteDot, []Cell{{0, 0}, {0, 1}}, 0}, []int{5, 3}, true},
+		{Constraint{WhiteDot, []Cell{{0, 0}, {0, 1}}, 2}, []int{5, 3}, false},
+		{Constraint{BlackDot, []Cell{{0, 0}, {0, 1}}, 0}, []int{3, 6}, false},
+		{Constraint{BlackDot, []Cell{{0, 0}, {0, 1}}, 0}, []int{3, 7}, true},
+		{Constraint{BlackDot, []Cell{{0, 0}, {0, 1}}, 0}, []int{3, 0}, false},
+	}
+
+	for _, test := range tests {
+		var sudoku Sudoku
+
+		for i, cell := range test.constraint.Cells {
+			if test.values[i] != 0 {
+				sudoku.SetValue(cell.Row, cell.Column, test.values[i])
+			}
+		}
+
+		if test.constraint.Violated(&sudoku) != test.violated {
+			t.Errorf("Sudoku: Constraint %v with values %v should be violated: %v",
+				test.constraint, test.values, test.violated)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package sudoku
+
+import (
+	"context"  // Cancelling generation.
+	"errors"   // Error handling.
+	"hash/fnv" // Seeds.
+	"sync"     // Shared cache.
+	"time"     // Dates.
+)
+
+// The layout of the dates of the daily puzzles.
+const dailyLayout = "2006-01-02"
+
+// The number of past days whose puzzles are kept by @Daily. Older puzzles are
+// generated again every time, so the cache doesn't grow with the archive.
+const dailyCacheDays = 31
+
+// Returns the calendar day of the time, at midnight UTC.
+func dailyDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DailySeed returns the seed of the generator of the puzzle of the day for the
+// calendar day of the date, in its own location, and the difficulty. It's the
+// FNV-1a hash of the day and the difficulty, so it doesn't depend on the
+// platform.
+func DailySeed(date time.Time, difficulty string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(dailyDay(date).Format(dailyLayout) + "/" + difficulty))
+
+	return int64(hash.Sum64())
+}
+
+// Daily serves the puzzle of the day of every difficulty. Every user gets the
+// same puzzle for the same day and difficulty, and the puzzles of the past
+// days stay available as an archive.
+type Daily struct {
+	// The first day of the archive.
+	start time.Time
+
+	clock Clock
+
+	mu    sync.Mutex
+	cache map[string]Sudoku
+}
+
+// Returns a daily puzzle service whose archive starts on the day of the date.
+func NewDaily(start time.Time) *Daily {
+	return &Daily{start: dailyDay(start), clock: systemClock{}, cache: map[string]Sudoku{}}
+}
+
+// Replaces the clock which tells the current day, like a fake one in tests.
+func (daily *Daily) SetClock(clock Clock) {
+	daily.clock = clock
+}
+
+// Returns the current day.
+func (daily *Daily) today() time.Time {
+	return dailyDay(daily.clock.Now())
+}
+
+// Returns the puzzle of the day of the date and the difficulty. The puzzle is
+// generated with @DailySeed the first time it's asked for, and cached if it's
+// one of the last dailyCacheDays days. An error is returned if the day is
+// before the start of the archive or after the current day, or if the
+// difficulty is unknown. Generating stops once the context is done, see
+// @Generator.GenerateContext.
+func (daily *Daily) Puzzle(ctx context.Context, date time.Time, difficulty string) (Sudoku, error) {
+	day := dailyDay(date)
+
+	if day.Before(daily.start) || day.After(daily.today()) {
+		return Sudoku{}, errors.New("Sudoku: No daily puzzle for the date.")
+	}
+
+	key := day.Format(dailyLayout) + "/" + difficulty
+
+	daily.mu.Lock()
+	sudoku, ok := daily.cache[key]
+	daily.mu.Unlock()
+
+	if ok {
+		return sudoku, nil
+	}
+
+	sudoku, err := NewGenerator(DailySeed(day, difficulty)).GenerateContext(ctx, difficulty, Rotational)
+	if err != nil {
+		return Sudoku{}, err
+	}
+
+	sudoku.metadata.Title = "Daily Sudoku"
+	sudoku.metadata.Date = day.Format(dailyLayout)
+
+	oldest := daily.today().AddDate(0, 0, -dailyCacheDays).Format(dailyLayout)
+
+	if sudoku.metadata.Date >= oldest {
+		daily.mu.Lock()
+		daily.cache[key] = sudoku
+
+		for old, cached := range daily.cache {
+			if cached.metadata.Date < oldest {
+				delete(daily.cache, old)
+			}
+		}
+
+		daily.mu.Unlock()
+	}
+
+	return sudoku, nil
+}
+
+// Returns the puzzle of the current day, see @Puzzle.
+func (daily *Daily) Today(ctx context.Context, difficulty string) (Sudoku, error) {
+	return daily.Puzzle(ctx, daily.today(), difficulty)
+}
+
+// Returns the days of the archive, from the current day back to the start of
+// the archive.
+func (daily *Daily) Archive() []time.Time {
+	return daily.ArchivePage(daily.today().AddDate(0, 0, 1), -1)
+}
+
+// Returns at most limit days of the archive before the day of the date, newest
+// first, or all of them if limit is negative. The page after it starts before
+// its last day.
+func (daily *Daily) ArchivePage(before time.Time, limit int) []time.Time {
+	days := []time.Time{}
+	day := dailyDay(before).AddDate(0, 0, -1)
+
+	if today := daily.today(); day.After(today) {
+		day = today
+	}
+
+	for ; !day.Before(daily.start) && len(days) != limit; day = day.AddDate(0, 0, -1) {
+		days = append(days, day)
+	}
+
+	return days
+}
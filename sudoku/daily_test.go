@@ -0,0 +1,83 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDaily(t *testing.T) {
+	clock := &fakeClock{time.Date(2025, 1, 1, 18, 30, 0, 0, time.UTC)}
+	daily := NewDaily(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	daily.SetClock(clock)
+
+	// Every user gets these puzzles, on any platform.
+	tests := []struct {
+		date       time.Time
+		difficulty string
+		puzzle     string
+	}{
+		{time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC), Easy, "...83..2.....6..538...94..7.45....1.6.......8.3....47.5..74...246..2.....2..81..."},
+		{time.Date(2024, 3, 14, 23, 59, 0, 0, time.UTC), Hard, "9.5.2....1..3...986..9.1....7....8..8...1...2..4....7....6.8..771...4..3....7.2.5"},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Easy, "2.6.8.4....72..6...9..571...39.26.......9.......74.29...317..6...8..29....2.6.8.4"},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Hard, "..7..9.......532...5.6..8..7...14..3.........6..39...1..5..1.4...974.......5..3.."},
+	}
+
+	for _, test := range tests {
+		sudoku, err := daily.Puzzle(context.Background(), test.date, test.difficulty)
+		if err != nil {
+			t.Fatalf("Sudoku: No %s puzzle for %v: %v", test.difficulty, test.date, err)
+		}
+
+		if gridString(sudoku.initialValues) != test.puzzle {
+			t.Errorf("Sudoku: %s puzzle of %v should be %s but is %s", test.difficulty, test.date, test.puzzle, gridString(sudoku.initialValues))
+		}
+
+		if metadata := sudoku.GetMetadata(); metadata.Difficulty != test.difficulty || metadata.Date != test.date.Format("2006-01-02") {
+			t.Errorf("Sudoku: Unexpected metadata %+v", metadata)
+		}
+	}
+
+	// The day depends on the location of the date.
+	local := time.Date(2025, 1, 1, 8, 0, 0, 0, time.FixedZone("UTC+9", 9*60*60))
+
+	if DailySeed(local, Easy) != DailySeed(tests[2].date, Easy) {
+		t.Errorf("Sudoku: The seed depends on the time of the day.")
+	}
+
+	if today, _ := daily.Today(context.Background(), Easy); gridString(today.initialValues) != tests[2].puzzle {
+		t.Errorf("Sudoku: Puzzle of today is not the one of 2025-01-01.")
+	}
+
+	for _, date := range []time.Time{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)} {
+		if _, err := daily.Puzzle(context.Background(), date, Easy); err == nil {
+			t.Errorf("Sudoku: Puzzle of %v is available.", date)
+		}
+	}
+
+	archive := daily.Archive()
+
+	if len(archive) != 367 || !archive[0].Equal(tests[2].date) || archive[366].Format("2006-01-02") != "2024-01-01" {
+		t.Errorf("Sudoku: Archive has %d days from %v to %v", len(archive), archive[0], archive[len(archive)-1])
+	}
+
+	if page := daily.ArchivePage(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), 2); len(page) != 2 || !page[0].Equal(archive[0]) || !page[1].Equal(archive[1]) {
+		t.Errorf("Sudoku: First page of the archive is %v", page)
+	}
+
+	if page := daily.ArchivePage(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), 5); len(page) != 2 || !page[1].Equal(archive[366]) {
+		t.Errorf("Sudoku: Last page of the archive is %v", page)
+	}
+
+	// Only the puzzles of the last days are cached.
+	if len(daily.cache) != 2 {
+		t.Errorf("Sudoku: Cache has %d puzzles instead of the 2 of 2025-01-01", len(daily.cache))
+	}
+
+	clock.Advance(40 * 24 * time.Hour)
+	daily.Today(context.Background(), Easy)
+
+	if len(daily.cache) != 1 {
+		t.Errorf("Sudoku: Cache kept %d puzzles of old days", len(daily.cache)-1)
+	}
+}
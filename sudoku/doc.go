@@ -0,0 +1,18 @@
+// Package sudoku reads, solves, rates, generates and plays sudoku puzzles.
+//
+// A @Sudoku holds the initial values of a puzzle, the values and candidates
+// entered by the player and optional variant constraints. Puzzles are read
+// with @Parse, @ReadSDK, @ReadSS, @DecodeFPuzzles, a @BinaryReader or as JSON,
+// and written back in the same formats or drawn with @Renderer, @RenderSVG,
+// @RenderPNG and @WriteBooklet.
+//
+// @Solve, @Rate, @NextHint and @Validate analyse a puzzle, and a @Generator
+// makes new ones of a given difficulty. A @Game plays a puzzle with undo and
+// redo, a timer, mistakes, hints, saves and a @MoveLog, and the @TUI plays it
+// on a terminal. @Batch, @NewServer, @Session and @Daily serve puzzles to many
+// players, and @Stats keeps the history of one of them.
+//
+// Files written by any version of the package, like saved games and move logs,
+// are still read by later ones. The command-line tool built on it lives in
+// cmd/sudoku.
+package sudoku
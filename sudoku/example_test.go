@@ -0,0 +1,147 @@
+package sudoku_test
+
+import (
+	"fmt"
+
+	"github.com/maucarrui/Sudoku2Go/sudoku"
+)
+
+const puzzle = "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+
+func Example() {
+	s, err := sudoku.Parse(puzzle)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	solved, err := s.Solve()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(solved.Line())
+	// Output:
+	// 534678912672195348198342567859761423426853791713924856961537284287419635345286179
+}
+
+func ExampleParse() {
+	s, err := sudoku.Parse("53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(s.FilledCount())
+
+	_, err = sudoku.Parse("123")
+	fmt.Println(err)
+	// Output:
+	// 30
+	// Sudoku: Expected 81 cells but found 3 (line 1, column 4).
+}
+
+func ExampleSudoku_Rate() {
+	s, _ := sudoku.Parse(puzzle)
+
+	rating, err := s.Rate()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(rating.Difficulty, rating.Hardest)
+	// Output:
+	// Easy Naked Single
+}
+
+func ExampleSudoku_NextHint() {
+	s, _ := sudoku.Parse(puzzle)
+
+	hint, err := s.NextHint()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(hint.Text(1))
+	fmt.Println(hint.Text(3))
+	// Output:
+	// Look for a Naked Single in block 5.
+	// Look for a Naked Single in block 5. Look at r5c5. Place 5 in r5c5.
+}
+
+func ExampleSudoku_Validate() {
+	s, _ := sudoku.Parse(puzzle)
+	validation := s.Validate()
+
+	fmt.Println(validation.Valid, validation.Solutions, validation.Minimal)
+	// Output:
+	// true 1 false
+}
+
+func ExampleSudoku_Conflicts() {
+	s, _ := sudoku.Parse(puzzle)
+	s.SetValue(0, 2, 5)
+
+	for _, conflict := range s.Conflicts() {
+		fmt.Println(conflict.Unit, conflict.Index+1, conflict.A, conflict.B, conflict.Value)
+	}
+	// Output:
+	// row 1 r1c1 r1c3 5
+	// block 1 r1c1 r1c3 5
+}
+
+func ExampleGenerator_Generate() {
+	generator := sudoku.NewGenerator(1)
+
+	s, err := generator.Generate(sudoku.Easy, sudoku.Rotational)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	rating, _ := s.Rate()
+	count, _ := s.CountSolutions(2)
+	fmt.Println(count, rating.Difficulty)
+	// Output:
+	// 1 Easy
+}
+
+func ExampleGame() {
+	s, _ := sudoku.Parse(puzzle)
+	game := sudoku.NewGame(s)
+
+	game.SetValue(0, 2, 4)
+	game.SetValue(0, 3, 1)
+	fmt.Println(game.GetMistakes())
+
+	game.Undo()
+	current := game.GetSudoku()
+	value, _ := current.GetValue(0, 3)
+	fmt.Println(value, game.CanRedo())
+	// Output:
+	// 1
+	// 0 true
+}
+
+func ExampleRenderer() {
+	s, _ := sudoku.Parse(puzzle)
+
+	fmt.Print(sudoku.Renderer{Mode: sudoku.ASCIIMode}.Render(&s))
+	// Output:
+	// +-------+-------+-------+
+	// | 5 3 . | . 7 . | . . . |
+	// | 6 . . | 1 9 5 | . . . |
+	// | . 9 8 | . . . | . 6 . |
+	// +-------+-------+-------+
+	// | 8 . . | . 6 . | . . 3 |
+	// | 4 . . | 8 . 3 | . . 1 |
+	// | 7 . . | . 2 . | . . 6 |
+	// +-------+-------+-------+
+	// | . 6 . | . . . | 2 8 . |
+	// | . . . | 4 1 9 | . . 5 |
+	// | . . . | . 8 . | . 7 9 |
+	// +-------+-------+-------+
+}
@@ -0,0 +1,283 @@
+package sudoku
+
+import (
+	"bufio"   // Line by line reading.
+	"fmt"     // Error formatting.
+	"io"      // Readers and writers.
+	"strings" // String manipulation.
+	"unicode" // Whitespace detection.
+)
+
+// Metadata tags of the SadMan format and the field of @Metadata they fill.
+var sdkTags = []struct {
+	tag   byte
+	field func(*Metadata) *string
+}{
+	{'A', func(m *Metadata) *string { return &m.Author }},
+	{'D', func(m *Metadata) *string { return &m.Description }},
+	{'C', func(m *Metadata) *string { return &m.Comment }},
+	{'B', func(m *Metadata) *string { return &m.Date }},
+	{'S', func(m *Metadata) *string { return &m.Source }},
+	{'L', func(m *Metadata) *string { return &m.Difficulty }},
+	{'U', func(m *Metadata) *string { return &m.SourceURL }},
+}
+
+// Reads the nine cells of a grid row. Digits are values, '.' and '0' are empty
+// cells, whitespace and separators are ignored.
+func parseRow(text string, line int) ([9]int, error) {
+	var row [9]int
+
+	k := 0
+	column := 0
+
+	for _, r := range text {
+		column++
+
+		if unicode.IsSpace(r) || isSeparator(r) {
+			continue
+		}
+
+		if r != '.' && (r < '0' || r > '9') {
+			return row, &ParseError{line, column, fmt.Sprintf("Unexpected character %q", r)}
+		}
+
+		if k >= 9 {
+			return row, &ParseError{line, column, "Too many cells in row"}
+		}
+
+		if r != '.' {
+			row[k] = int(r - '0')
+		}
+
+		k++
+	}
+
+	if k < 9 {
+		return row, &ParseError{line, column + 1, fmt.Sprintf("Expected 9 cells in row but found %d", k)}
+	}
+
+	return row, nil
+}
+
+// Returns true if the line only draws a border of the grid.
+func isBorder(text string) bool {
+	return strings.Trim(text, "*-+| \t") == ""
+}
+
+// ReadSDK reads a puzzle in the SadMan Software format (.sdk). Lines starting
+// with '#' hold the metadata, followed by the nine rows of the puzzle. The
+// current progress can be stored after the puzzle in a "[State]" section, in
+// which case the puzzle rows go in a "[Puzzle]" section.
+func ReadSDK(r io.Reader) (Sudoku, error) {
+	var sudoku Sudoku
+	var rows [2][][9]int
+	var lines []int
+
+	section := 0
+	line := 0
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		switch {
+		case strings.TrimSpace(text) == "":
+			continue
+
+		case strings.HasPrefix(text, "#"):
+			if len(text) < 2 {
+				continue
+			}
+
+			for _, t := range sdkTags {
+				if t.tag == text[1] {
+					*t.field(&sudoku.metadata) = strings.TrimSpace(text[2:])
+				}
+			}
+
+		case strings.EqualFold(strings.TrimSpace(text), "[Puzzle]"):
+			section = 0
+
+		case strings.EqualFold(strings.TrimSpace(text), "[State]"):
+			section = 1
+
+		case strings.HasPrefix(strings.TrimSpace(text), "["):
+			return Sudoku{}, &ParseError{line, 1, fmt.Sprintf("Unknown section %s", strings.TrimSpace(text))}
+
+		default:
+			if len(rows[section]) == 9 {
+				return Sudoku{}, &ParseError{line, 1, "Too many rows"}
+			}
+
+			row, err := parseRow(text, line)
+			if err != nil {
+				return Sudoku{}, err
+			}
+
+			rows[section] = append(rows[section], row)
+
+			if section == 1 {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Sudoku{}, err
+	}
+
+	if len(rows[0]) != 9 {
+		return Sudoku{}, &ParseError{line + 1, 1, fmt.Sprintf("Expected 9 rows but found %d", len(rows[0]))}
+	}
+
+	for i, row := range rows[0] {
+		for j, val := range row {
+			if val != 0 {
+				sudoku.SetInitialValue(i, j, val)
+			}
+		}
+	}
+
+	if len(rows[1]) != 0 && len(rows[1]) != 9 {
+		return Sudoku{}, &ParseError{line + 1, 1, fmt.Sprintf("Expected 9 state rows but found %d", len(rows[1]))}
+	}
+
+	for i, row := range rows[1] {
+		for j, val := range row {
+			if sudoku.initialValues[i][j] != 0 {
+				if val != 0 && val != sudoku.initialValues[i][j] {
+					return Sudoku{}, &ParseError{lines[i], j + 1, fmt.Sprintf("State overwrites initial value on (%d, %d)", i, j)}
+				}
+			} else if val != 0 {
+				sudoku.SetValue(i, j, val)
+			}
+		}
+	}
+
+	return sudoku, nil
+}
+
+// Returns true if any cell has a value which is not an initial value.
+func (sudoku *Sudoku) hasProgress() bool {
+	return sudoku.values != sudoku.initialValues
+}
+
+// Returns a grid row in the SadMan format, empty cells are written as '.'.
+func sdkRow(row [9]int) string {
+	var b strings.Builder
+
+	for _, val := range row {
+		if val == 0 {
+			b.WriteByte('.')
+		} else {
+			b.WriteByte(byte('0' + val))
+		}
+	}
+
+	return b.String()
+}
+
+// WriteSDK writes the sudoku in the SadMan Software format (.sdk), see
+// @ReadSDK. The metadata is always written, and the current progress only if
+// any cell besides the initial values is filled.
+func (sudoku *Sudoku) WriteSDK(w io.Writer) error {
+	var b strings.Builder
+
+	for _, t := range sdkTags {
+		if val := *t.field(&sudoku.metadata); val != "" {
+			fmt.Fprintf(&b, "#%c%s\n", t.tag, val)
+		}
+	}
+
+	progress := sudoku.hasProgress()
+
+	if progress {
+		b.WriteString("[Puzzle]\n")
+	}
+
+	for _, row := range sudoku.initialValues {
+		b.WriteString(sdkRow(row) + "\n")
+	}
+
+	if progress {
+		b.WriteString("[State]\n")
+
+		for _, row := range sudoku.values {
+			b.WriteString(sdkRow(row) + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ReadSS reads a puzzle in the Simple Sudoku format (.ss). The puzzle is drawn
+// as nine rows of cells separated by '|', with '.' for the empty cells, and
+// border lines made of '*', '-', '+' and '|' which are ignored. This format
+// only holds the initial values.
+func ReadSS(r io.Reader) (Sudoku, error) {
+	var sudoku Sudoku
+
+	i := 0
+	line := 0
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		if isBorder(text) {
+			continue
+		}
+
+		if i == 9 {
+			return Sudoku{}, &ParseError{line, 1, "Too many rows"}
+		}
+
+		row, err := parseRow(text, line)
+		if err != nil {
+			return Sudoku{}, err
+		}
+
+		for j, val := range row {
+			if val != 0 {
+				sudoku.SetInitialValue(i, j, val)
+			}
+		}
+
+		i++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Sudoku{}, err
+	}
+
+	if i != 9 {
+		return Sudoku{}, &ParseError{line + 1, 1, fmt.Sprintf("Expected 9 rows but found %d", i)}
+	}
+
+	return sudoku, nil
+}
+
+// WriteSS writes the initial values of the sudoku in the Simple Sudoku format
+// (.ss), see @ReadSS.
+func (sudoku *Sudoku) WriteSS(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("*-----------*\n")
+
+	for i, row := range sudoku.initialValues {
+		line := sdkRow(row)
+		b.WriteString("|" + line[0:3] + "|" + line[3:6] + "|" + line[6:9] + "|\n")
+
+		if i == 2 || i == 5 {
+			b.WriteString("|---+---+---|\n")
+		}
+	}
+
+	b.WriteString("*-----------*\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
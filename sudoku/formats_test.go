@@ -0,0 +1,126 @@
+package sudoku
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSDK(t *testing.T) {
+	file := `#AJohn Doe
+#SThe Daily Paper
+#LHard
+2..1.5..3
+.54...71.
+.1.2.3.8.
+6.28.73.4
+.........
+1.53.98.6
+.2.7.1.6.
+.81...24.
+7..4.2..1
+`
+
+	sudoku, err := ReadSDK(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Sudoku: Can't read valid .sdk file: %v", err)
+	}
+
+	metadata := sudoku.GetMetadata()
+	if metadata.Author != "John Doe" || metadata.Source != "The Daily Paper" || metadata.Difficulty != "Hard" {
+		t.Errorf("Sudoku: Metadata was not read correctly: %+v", metadata)
+	}
+
+	if val, _ := sudoku.GetValue(0, 0); val != 2 {
+		t.Errorf("Sudoku: Value on (0, 0) should be 2 but is %d", val)
+	}
+
+	if sudoku.SetValue(0, 0, 1) == nil {
+		t.Errorf("Sudoku: Value on (0, 0) is not an initial value.")
+	}
+
+	// Without progress, writing must return the same file.
+	var b bytes.Buffer
+	sudoku.WriteSDK(&b)
+
+	if b.String() != file {
+		t.Errorf("Sudoku: Wrote\n%s\ninstead of\n%s", b.String(), file)
+	}
+
+	// With progress, the state must be preserved as well.
+	sudoku.SetValue(0, 1, 6)
+	sudoku.SetValue(8, 8, 1)
+
+	b.Reset()
+	sudoku.WriteSDK(&b)
+
+	other, err := ReadSDK(&b)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't read written .sdk file: %v", err)
+	}
+
+	if !reflect.DeepEqual(other, sudoku) {
+		t.Errorf("Sudoku: Read\n%v\ninstead of\n%v", other.ToString(), sudoku.ToString())
+	}
+
+	if other.SetValue(0, 1, 7) != nil {
+		t.Errorf("Sudoku: Progress was read as initial value.")
+	}
+}
+
+func TestSS(t *testing.T) {
+	file := `*-----------*
+|2..|1.5|..3|
+|.54|...|71.|
+|.1.|2.3|.8.|
+|---+---+---|
+|6.2|8.7|3.4|
+|...|...|...|
+|1.5|3.9|8.6|
+|---+---+---|
+|.2.|7.1|.6.|
+|.81|...|24.|
+|7..|4.2|..1|
+*-----------*
+`
+
+	sudoku, err := ReadSS(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Sudoku: Can't read valid .ss file: %v", err)
+	}
+
+	if val, _ := sudoku.GetValue(8, 8); val != 1 {
+		t.Errorf("Sudoku: Value on (8, 8) should be 1 but is %d", val)
+	}
+
+	var b bytes.Buffer
+	sudoku.WriteSS(&b)
+
+	if b.String() != file {
+		t.Errorf("Sudoku: Wrote\n%s\ninstead of\n%s", b.String(), file)
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	var perr *ParseError
+
+	// Unexpected character on the third line, fifth column.
+	_, err := ReadSDK(strings.NewReader("#AJohn\n2..1.5..3\n.54.x.71.\n"))
+	if !errors.As(err, &perr) || perr.Line != 3 || perr.Column != 5 {
+		t.Errorf("Sudoku: Expected error on (3, 5) but got %v", err)
+	}
+
+	// Missing rows are reported after the last line.
+	_, err = ReadSS(strings.NewReader("*-----------*\n|2..|1.5|..3|\n"))
+	if !errors.As(err, &perr) || perr.Line != 3 {
+		t.Errorf("Sudoku: Expected error on line 3 but got %v", err)
+	}
+
+	// A short row is reported on the column after its end.
+	_, err = ReadSS(strings.NewReader("*-----------*\n|2..|1.5|..|\n"))
+	if !errors.As(err, &perr) || perr.Line != 2 || perr.Column != 13 {
+		t.Errorf("Sudoku: Expected error on (2, 13) but got %v", err)
+	}
+}
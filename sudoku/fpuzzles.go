@@ -0,0 +1,473 @@
+package sudoku
+
+import (
+	"encoding/json" // The puzzles are stored as JSON.
+	"errors"        // Error handling.
+	"fmt"           // Error formatting.
+	"net/url"       // Unescaping of links.
+	"strconv"       // String Conversions.
+	"strings"       // String manipulation.
+)
+
+// The puzzle format of f-puzzles, which SudokuPad also understands. Only the
+// fields which have a meaning in @Sudoku are read.
+type fpPuzzle struct {
+	Size        int        `json:"size"`
+	Title       string     `json:"title,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Ruleset     string     `json:"ruleset,omitempty"`
+	Grid        [][]fpCell `json:"grid"`
+	KillerCage  []fpShape  `json:"killercage,omitempty"`
+	Thermometer []fpShape  `json:"thermometer,omitempty"`
+	Arrow       []fpShape  `json:"arrow,omitempty"`
+	Palindrome  []fpShape  `json:"palindrome,omitempty"`
+	Difference  []fpShape  `json:"difference,omitempty"`
+	Ratio       []fpShape  `json:"ratio,omitempty"`
+	ExtraRegion []fpShape  `json:"extraregion,omitempty"`
+	DiagonalPos bool       `json:"diagonal+,omitempty"`
+	DiagonalNeg bool       `json:"diagonal-,omitempty"`
+}
+
+type fpCell struct {
+	Value             int   `json:"value,omitempty"`
+	Given             bool  `json:"given,omitempty"`
+	CenterPencilMarks []int `json:"centerPencilMarks,omitempty"`
+	CornerPencilMarks []int `json:"cornerPencilMarks,omitempty"`
+	Region            *int  `json:"region,omitempty"`
+}
+
+// A cage, line or dot. Cells are written as "R1C1".
+type fpShape struct {
+	Cells []string   `json:"cells,omitempty"`
+	Lines [][]string `json:"lines,omitempty"`
+	Value fpNumber   `json:"value,omitempty"`
+}
+
+// f-puzzles writes numbers like the sum of a cage as strings.
+type fpNumber int
+
+func (n fpNumber) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.Itoa(int(n)))
+}
+
+func (n *fpNumber) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		var val int
+
+		if err := json.Unmarshal(data, &val); err != nil {
+			return err
+		}
+
+		*n = fpNumber(val)
+		return nil
+	}
+
+	if s == "" {
+		*n = 0
+		return nil
+	}
+
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("Sudoku: Invalid number %q.", s)
+	}
+
+	*n = fpNumber(val)
+	return nil
+}
+
+// Fields of f-puzzles which don't change the solution of the puzzle, and can be
+// ignored.
+var fpIgnored = map[string]bool{
+	"size": true, "title": true, "author": true, "ruleset": true, "grid": true,
+	"killercage": true, "thermometer": true, "arrow": true, "palindrome": true,
+	"difference": true, "ratio": true, "extraregion": true, "diagonal+": true,
+	"diagonal-": true, "text": true, "line": true, "circle": true,
+	"rectangle": true, "cage": true, "solution": true, "successMessage": true,
+	"highlightConflicts": true, "disabledlogic": true,
+	"truecandidatesoptions": true,
+}
+
+// The cells of both diagonals, from the top row to the bottom one.
+func diagonals() (neg, pos []Cell) {
+	for i := 0; i < 9; i++ {
+		neg = append(neg, Cell{i, i})
+		pos = append(pos, Cell{i, 8 - i})
+	}
+
+	return neg, pos
+}
+
+// Returns the cell of a reference like "R1C1".
+func parseCellRef(ref string) (Cell, error) {
+	var row, col int
+
+	if _, err := fmt.Sscanf(strings.ToUpper(ref), "R%dC%d", &row, &col); err != nil {
+		return Cell{}, fmt.Errorf("Sudoku: Invalid cell %q.", ref)
+	}
+
+	if row < 1 || row > 9 || col < 1 || col > 9 {
+		return Cell{}, fmt.Errorf("Sudoku: Invalid cell %q.", ref)
+	}
+
+	return Cell{row - 1, col - 1}, nil
+}
+
+// Returns the cells of a list of references.
+func parseCellRefs(refs []string) ([]Cell, error) {
+	var cells []Cell
+
+	for _, ref := range refs {
+		cell, err := parseCellRef(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		cells = append(cells, cell)
+	}
+
+	return cells, nil
+}
+
+// Returns the references of a list of cells.
+func cellRefs(cells []Cell) []string {
+	var refs []string
+
+	for _, cell := range cells {
+		refs = append(refs, fmt.Sprintf("R%dC%d", cell.Row+1, cell.Column+1))
+	}
+
+	return refs
+}
+
+// Returns the lines of an arrow whose cells were read by @DecodeFPuzzles, the
+// circle first and then the new cells of each line. A cell continues the line
+// of the latest cell it touches, so each branch of the arrow is kept as its own
+// line starting at the circle.
+func arrowLines(cells []Cell) [][]string {
+	touches := func(a, b Cell) bool {
+		return a != b && a.Row-b.Row <= 1 && b.Row-a.Row <= 1 && a.Column-b.Column <= 1 && b.Column-a.Column <= 1
+	}
+
+	lines := [][]Cell{}
+
+	for _, cell := range cells[1:] {
+		if n := len(lines); n > 0 && touches(lines[n-1][len(lines[n-1])-1], cell) {
+			lines[n-1] = append(lines[n-1], cell)
+			continue
+		}
+
+		line := []Cell{cells[0], cell}
+
+	search:
+		for l := len(lines) - 1; l >= 0; l-- {
+			for k := len(lines[l]) - 1; k >= 1; k-- {
+				if touches(lines[l][k], cell) {
+					line = append(append([]Cell{}, lines[l][:k+1]...), cell)
+					break search
+				}
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, cells[:1])
+	}
+
+	refs := make([][]string, len(lines))
+	for i, line := range lines {
+		refs[i] = cellRefs(line)
+	}
+
+	return refs
+}
+
+// Returns the f-puzzles JSON of a link or of its compressed payload. Links of
+// f-puzzles carry the payload in the "load" parameter, and links of SudokuPad
+// after the "fpuzzles" prefix.
+func fpPayload(link string) (string, error) {
+	payload := strings.TrimSpace(link)
+
+	if i := strings.Index(payload, "load="); i >= 0 {
+		payload = payload[i+len("load="):]
+
+		if j := strings.IndexByte(payload, '&'); j >= 0 {
+			payload = payload[:j]
+		}
+	} else if i := strings.Index(payload, "fpuzzles"); i >= 0 {
+		payload = payload[i+len("fpuzzles"):]
+	} else if strings.Contains(payload, "://") {
+		return "", errors.New("Sudoku: Unsupported puzzle link.")
+	}
+
+	// Links are sometimes escaped, but '+' is part of the Base64 alphabet and
+	// must not be read as a space.
+	payload, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return lzDecompressFromBase64(payload)
+}
+
+// DecodeFPuzzles reads a puzzle made with f-puzzles or SudokuPad. The input is
+// either a link to the puzzle or its compressed payload. Givens, entered
+// values, pencil marks, killer cages, thermometers, arrows, palindromes, dots,
+// extra regions and diagonals are read as values and @Constraint. An error is
+// returned for any other rule, so a puzzle is never solved without one of its
+// constraints.
+//
+// The 3x3 blocks are part of every @Sudoku, so irregular (jigsaw) regions are
+// out of scope: a grid whose cells give regions other than the standard blocks
+// is rejected instead of being solved with the wrong blocks.
+func DecodeFPuzzles(link string) (Sudoku, error) {
+	var sudoku Sudoku
+	var puzzle fpPuzzle
+	var fields map[string]json.RawMessage
+
+	data, err := fpPayload(link)
+	if err != nil {
+		return Sudoku{}, err
+	}
+
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return Sudoku{}, fmt.Errorf("Sudoku: Invalid f-puzzles data: %w", err)
+	}
+
+	for name, raw := range fields {
+		switch strings.TrimSpace(string(raw)) {
+		case "null", "false", "[]", `""`:
+			continue
+		}
+
+		if !fpIgnored[name] {
+			return Sudoku{}, fmt.Errorf("Sudoku: Unsupported f-puzzles constraint %q.", name)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(data), &puzzle); err != nil {
+		return Sudoku{}, fmt.Errorf("Sudoku: Invalid f-puzzles data: %w", err)
+	}
+
+	if puzzle.Size != 9 || len(puzzle.Grid) != 9 {
+		return Sudoku{}, errors.New("Sudoku: Only 9x9 puzzles are supported.")
+	}
+
+	for i, row := range puzzle.Grid {
+		if len(row) != 9 {
+			return Sudoku{}, errors.New("Sudoku: Only 9x9 puzzles are supported.")
+		}
+
+		for j, cell := range row {
+			if cell.Region != nil && *cell.Region != (i/3)*3+j/3 {
+				return Sudoku{}, errors.New("Sudoku: Irregular (jigsaw) regions are not supported, only the 3x3 blocks.")
+			}
+
+			if cell.Value != 0 {
+				if cell.Given {
+					err = sudoku.SetInitialValue(i, j, cell.Value)
+				} else {
+					err = sudoku.SetValue(i, j, cell.Value)
+				}
+
+				if err != nil {
+					return Sudoku{}, err
+				}
+
+				continue
+			}
+
+			marks := cell.CenterPencilMarks
+			if len(marks) == 0 {
+				marks = cell.CornerPencilMarks
+			}
+
+			if err := sudoku.SetCandidates(i, j, marks); err != nil {
+				return Sudoku{}, err
+			}
+		}
+	}
+
+	// Adds a constraint on the referenced cells.
+	add := func(kind string, refs []string, value fpNumber) error {
+		cells, err := parseCellRefs(refs)
+		if err != nil {
+			return err
+		}
+
+		return sudoku.AddConstraint(Constraint{kind, cells, int(value)})
+	}
+
+	for _, shape := range puzzle.KillerCage {
+		if err := add(Cage, shape.Cells, shape.Value); err != nil {
+			return Sudoku{}, err
+		}
+	}
+
+	for _, shape := range puzzle.ExtraRegion {
+		if err := add(Region, shape.Cells, 0); err != nil {
+			return Sudoku{}, err
+		}
+	}
+
+	for _, shape := range puzzle.Difference {
+		if err := add(WhiteDot, shape.Cells, shape.Value); err != nil {
+			return Sudoku{}, err
+		}
+	}
+
+	for _, shape := range puzzle.Ratio {
+		if err := add(BlackDot, shape.Cells, shape.Value); err != nil {
+			return Sudoku{}, err
+		}
+	}
+
+	for _, shape := range puzzle.Thermometer {
+		for _, line := range shape.Lines {
+			if err := add(Thermometer, line, 0); err != nil {
+				return Sudoku{}, err
+			}
+		}
+	}
+
+	for _, shape := range puzzle.Palindrome {
+		for _, line := range shape.Lines {
+			if err := add(Palindrome, line, 0); err != nil {
+				return Sudoku{}, err
+			}
+		}
+	}
+
+	for _, shape := range puzzle.Arrow {
+		if len(shape.Cells) != 1 {
+			return Sudoku{}, errors.New("Sudoku: Only arrows with a single cell circle are supported.")
+		}
+
+		// The lines start on the circle and may share cells when branching.
+		refs := []string{shape.Cells[0]}
+		seen := map[string]bool{strings.ToUpper(shape.Cells[0]): true}
+
+		for _, line := range shape.Lines {
+			for _, ref := range line {
+				if !seen[strings.ToUpper(ref)] {
+					seen[strings.ToUpper(ref)] = true
+					refs = append(refs, ref)
+				}
+			}
+		}
+
+		if err := add(Arrow, refs, 0); err != nil {
+			return Sudoku{}, err
+		}
+	}
+
+	neg, pos := diagonals()
+
+	if puzzle.DiagonalNeg {
+		sudoku.AddConstraint(Constraint{Region, neg, 0})
+	}
+
+	if puzzle.DiagonalPos {
+		sudoku.AddConstraint(Constraint{Region, pos, 0})
+	}
+
+	sudoku.metadata.Title = puzzle.Title
+	sudoku.metadata.Author = puzzle.Author
+	sudoku.metadata.Rules = puzzle.Ruleset
+
+	return sudoku, nil
+}
+
+// Returns true if both lists hold the same cells in the same order.
+func sameCells(a, b []Cell) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EncodeFPuzzles returns the compressed f-puzzles payload of the sudoku, which
+// can be read back with @DecodeFPuzzles. See @FPuzzlesURL and @SudokuPadURL
+// for complete links.
+func (sudoku *Sudoku) EncodeFPuzzles() (string, error) {
+	puzzle := fpPuzzle{
+		Size:    9,
+		Title:   sudoku.metadata.Title,
+		Author:  sudoku.metadata.Author,
+		Ruleset: sudoku.metadata.Rules,
+		Grid:    make([][]fpCell, 9),
+	}
+
+	for i := 0; i < 9; i++ {
+		puzzle.Grid[i] = make([]fpCell, 9)
+
+		for j := 0; j < 9; j++ {
+			puzzle.Grid[i][j] = fpCell{
+				Value:             sudoku.values[i][j],
+				Given:             sudoku.initialValues[i][j] != 0,
+				CenterPencilMarks: maskDigits(sudoku.candidates[i][j]),
+			}
+		}
+	}
+
+	neg, pos := diagonals()
+
+	for _, constraint := range sudoku.constraints {
+		shape := fpShape{Cells: cellRefs(constraint.Cells), Value: fpNumber(constraint.Value)}
+
+		switch constraint.Type {
+		case Cage:
+			puzzle.KillerCage = append(puzzle.KillerCage, shape)
+		case WhiteDot:
+			puzzle.Difference = append(puzzle.Difference, shape)
+		case BlackDot:
+			puzzle.Ratio = append(puzzle.Ratio, shape)
+		case Thermometer:
+			puzzle.Thermometer = append(puzzle.Thermometer, fpShape{Lines: [][]string{shape.Cells}})
+		case Palindrome:
+			puzzle.Palindrome = append(puzzle.Palindrome, fpShape{Lines: [][]string{shape.Cells}})
+		case Arrow:
+			puzzle.Arrow = append(puzzle.Arrow, fpShape{Cells: shape.Cells[:1], Lines: arrowLines(constraint.Cells)})
+		case Region:
+			if sameCells(constraint.Cells, neg) {
+				puzzle.DiagonalNeg = true
+			} else if sameCells(constraint.Cells, pos) {
+				puzzle.DiagonalPos = true
+			} else {
+				puzzle.ExtraRegion = append(puzzle.ExtraRegion, fpShape{Cells: shape.Cells})
+			}
+		}
+	}
+
+	data, err := json.Marshal(puzzle)
+	if err != nil {
+		return "", err
+	}
+
+	return lzCompressToBase64(string(data)), nil
+}
+
+// Returns a link to open the sudoku in f-puzzles.
+func (sudoku *Sudoku) FPuzzlesURL() (string, error) {
+	payload, err := sudoku.EncodeFPuzzles()
+
+	return "https://www.f-puzzles.com/?load=" + payload, err
+}
+
+// Returns a link to open the sudoku in SudokuPad.
+func (sudoku *Sudoku) SudokuPadURL() (string, error) {
+	payload, err := sudoku.EncodeFPuzzles()
+
+	return "https://sudokupad.app/fpuzzles" + payload, err
+}
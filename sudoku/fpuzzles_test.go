@@ -0,0 +1,152 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFPuzzles(t *testing.T) {
+	data := `{"size":9,"title":"Tiny killer","author":"Jane","ruleset":"Normal sudoku rules apply.",
+"grid":[[{"value":5,"given":true,"region":0},{},{},{},{},{},{},{},{}],
+[{},{"value":3},{},{},{},{},{},{},{}],
+[{"centerPencilMarks":[1,2]},{},{},{},{},{},{},{},{}],
+[{},{},{},{},{},{},{},{},{}],[{},{},{},{},{},{},{},{},{}],[{},{},{},{},{},{},{},{},{}],
+[{},{},{},{},{},{},{},{},{}],[{},{},{},{},{},{},{},{},{}],[{},{},{},{},{},{},{},{},{"cornerPencilMarks":[9]}]],
+"killercage":[{"cells":["R1C2","R1C3"],"value":"10"}],
+"thermometer":[{"lines":[["R4C1","R4C2","R4C3"],["R4C1","R5C1"]]}],
+"arrow":[{"cells":["R6C6"],"lines":[["R6C6","R7C7","R8C8"],["R6C6","R7C7","R8C6"]]}],
+"ratio":[{"cells":["R9C1","R9C2"]}],
+"diagonal-":true,
+"text":[{"cells":["R1C1"],"value":"cosmetic"}]}`
+
+	sudoku, err := DecodeFPuzzles("https://www.f-puzzles.com/?load=" + lzCompressToBase64(data))
+	if err != nil {
+		t.Fatalf("Sudoku: Can't decode f-puzzles link: %v", err)
+	}
+
+	if val, _ := sudoku.GetValue(0, 0); val != 5 || sudoku.SetValue(0, 0, 1) == nil {
+		t.Errorf("Sudoku: Given on (0, 0) was not read.")
+	}
+
+	if val, _ := sudoku.GetValue(1, 1); val != 3 || sudoku.SetValue(1, 1, 3) != nil {
+		t.Errorf("Sudoku: Value on (1, 1) was not read.")
+	}
+
+	if vals, _ := sudoku.GetCandidates(2, 0); !reflect.DeepEqual(vals, []int{1, 2}) {
+		t.Errorf("Sudoku: Candidates of (2, 0) should be [1 2] but are %v", vals)
+	}
+
+	if vals, _ := sudoku.GetCandidates(8, 8); !reflect.DeepEqual(vals, []int{9}) {
+		t.Errorf("Sudoku: Candidates of (8, 8) should be [9] but are %v", vals)
+	}
+
+	neg, _ := diagonals()
+	expected := []Constraint{
+		{Cage, []Cell{{0, 1}, {0, 2}}, 10},
+		{BlackDot, []Cell{{8, 0}, {8, 1}}, 0},
+		{Thermometer, []Cell{{3, 0}, {3, 1}, {3, 2}}, 0},
+		{Thermometer, []Cell{{3, 0}, {4, 0}}, 0},
+		{Arrow, []Cell{{5, 5}, {6, 6}, {7, 7}, {7, 5}}, 0},
+		{Region, neg, 0},
+	}
+
+	if !reflect.DeepEqual(sudoku.GetConstraints(), expected) {
+		t.Errorf("Sudoku: Constraints should be %v but are %v", expected, sudoku.GetConstraints())
+	}
+
+	if metadata := sudoku.GetMetadata(); metadata.Title != "Tiny killer" || metadata.Author != "Jane" {
+		t.Errorf("Sudoku: Metadata was not read: %+v", metadata)
+	}
+
+	// Each branch of an arrow is written as its own line.
+	payload, _ := sudoku.EncodeFPuzzles()
+	data, _ = lzDecompressFromBase64(payload)
+	var encoded fpPuzzle
+
+	if err := json.Unmarshal([]byte(data), &encoded); err != nil {
+		t.Fatalf("Sudoku: Can't read own f-puzzles data: %v", err)
+	}
+
+	lines := [][]string{{"R6C6", "R7C7", "R8C8"}, {"R6C6", "R7C7", "R8C6"}}
+	if len(encoded.Arrow) != 1 || !reflect.DeepEqual(encoded.Arrow[0].Lines, lines) {
+		t.Errorf("Sudoku: Arrow should be written with lines %v but is %+v", lines, encoded.Arrow)
+	}
+
+	// Encoding and decoding must return the same sudoku, with SudokuPad links as
+	// well.
+	link, _ := sudoku.SudokuPadURL()
+
+	other, err := DecodeFPuzzles(link)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't decode own link %s: %v", link, err)
+	}
+
+	if !reflect.DeepEqual(other, sudoku) {
+		t.Errorf("Sudoku: Decoded\n%v\ninstead of\n%v", other.ToString(), sudoku.ToString())
+	}
+}
+
+func TestFPuzzlesLink(t *testing.T) {
+	// A link whose payload was compressed by the JavaScript lz-string, with
+	// the givens of the classic puzzle and a cage of 10 on R1C3 and R1C4.
+	link := "https://www.f-puzzles.com/?load=N4IgzglgXgpiBcBOANCALhNAbO8QGEsBDMSAYxFSIFc0ALAewCcEQB1CAawgAcYATCEUogm1HGBhpWAOWYBbIlgAEYavwadqysROVEePLAE8AdCIDmTCPwQBtO6ABuS6rgCsqCxCcwAdghoYjAAvsjOrrgAzF4+/oHBYcBJSSAuWG4IAOyxvgHwQW4p4cXJALrIjmmRCABsufEFiSUtERm4AIwN+YWh4dXtCCgg3nkJRf3pmfCeI3E9zcmtIRWOqVO4w6ONves18AAc3eN9S2elA9P1c2NNE+WVbdNHNzuLpXuD8NfbC/cfk32MVef1CqyeuAALMc7qcLhsEC9fid4UCYbtloCvl0QSjwZdcDlcbCAWcCQgAEzo96YiF1alFcGfK4MuG05m4KnEjF0w6slJM2nk+DQ7mLYU45EkrHTLbzFFChEzfmC86tYVI+XStW8olSnnCuW3XZlCogbhYHBMMhECy4KpkGCWsD2EAAJQ6+CiIg9+EhIDNSpAHQADCAViEgA=="
+
+	sudoku, err := DecodeFPuzzles(link)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't decode f-puzzles link: %v", err)
+	}
+
+	if givens := sudoku.GivensLine(); givens != "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79" {
+		t.Errorf("Sudoku: Givens of the link are %s", givens)
+	}
+
+	expected := []Constraint{{Cage, []Cell{{0, 2}, {0, 3}}, 10}}
+
+	if !reflect.DeepEqual(sudoku.GetConstraints(), expected) {
+		t.Errorf("Sudoku: Constraints should be %v but are %v", expected, sudoku.GetConstraints())
+	}
+
+	if metadata := sudoku.GetMetadata(); metadata.Title != "Classic" || metadata.Author != "Wikipedia" {
+		t.Errorf("Sudoku: Metadata of the link is %+v", metadata)
+	}
+}
+
+func TestFPuzzlesErrors(t *testing.T) {
+	grid := "[" + strings.Repeat("[{},{},{},{},{},{},{},{},{}],", 8) + "[{},{},{},{},{},{},{},{},{}]]"
+
+	inputs := []string{
+		`{"size":6,"grid":[]}`,
+		`{"size":9,"grid":` + grid + `,"sandwichsum":[{"cell":"R0C1","value":"10"}]}`,
+		`{"size":9,"grid":` + strings.Replace(grid, "{}", `{"region":4}`, 1) + `}`,
+		`{"size":9,"grid":` + grid + `,"killercage":[{"cells":["R1C10"]}]}`,
+	}
+
+	for _, input := range inputs {
+		if _, err := DecodeFPuzzles(lzCompressToBase64(input)); err == nil {
+			t.Errorf("Sudoku: Accepts invalid puzzle %s", input)
+		}
+	}
+
+	// Regions are only accepted when they are the standard blocks.
+	regions := grid
+	for i := 0; i < 81; i++ {
+		regions = strings.Replace(regions, "{}", fmt.Sprintf(`{"region":%d}`, (i/9/3)*3+i%9/3), 1)
+	}
+
+	if _, err := DecodeFPuzzles(lzCompressToBase64(`{"size":9,"grid":` + regions + `}`)); err != nil {
+		t.Errorf("Sudoku: Rejects puzzle with the standard regions: %v", err)
+	}
+
+	jigsaw := strings.Replace(regions, `{"region":0}`, `{"region":1}`, 1)
+	if _, err := DecodeFPuzzles(lzCompressToBase64(`{"size":9,"grid":` + jigsaw + `}`)); err == nil || !strings.Contains(err.Error(), "jigsaw") {
+		t.Errorf("Sudoku: Jigsaw regions should be rejected but got %v", err)
+	}
+
+	// Empty unknown constraints are harmless.
+	if _, err := DecodeFPuzzles(lzCompressToBase64(`{"size":9,"grid":` + grid + `,"sandwichsum":[]}`)); err != nil {
+		t.Errorf("Sudoku: Rejects puzzle without constraints: %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package sudoku
+
+import (
+	"errors" // Error handling.
+	"time"   // Elapsed time.
+)
+
+// The state of a cell before and after a move.
+type cellChange struct {
+	cell             Cell
+	beforeValue      int
+	afterValue       int
+	beforeCandidates uint16
+	afterCandidates  uint16
+}
+
+// Returns the change which reverts this one.
+func (change cellChange) inverse() cellChange {
+	return cellChange{change.cell, change.afterValue, change.beforeValue, change.afterCandidates, change.beforeCandidates}
+}
+
+// Game is a sudoku being played. Every move goes through the game, which keeps
+// the history of moves so they can be undone and redone.
+type Game struct {
+	sudoku Sudoku
+
+	// Each step of the history is a list of changes undone together.
+	undo [][]cellChange
+	redo [][]cellChange
+
+	// The changes of the group in progress, see @Group.
+	group    []cellChange
+	grouping int
+
+	// The number of wrong values entered, and the number of them which ends
+	// the game, 0 if there is no limit.
+	mistakes     int
+	mistakeLimit int
+
+	// The number of hints taken, see @NextHint.
+	hints int
+
+	// The time played before the current run of the timer, and the start of
+	// the current run, zero while paused. See @Elapsed.
+	clock   Clock
+	elapsed time.Duration
+	started time.Time
+
+	// The time the sudoku was completed, zero until then, and the functions
+	// called at that moment, see @OnComplete.
+	completed  time.Time
+	onComplete []func(game *Game)
+
+	// Called after every step added to the history, see @SetAutosave.
+	autosave      func(game *Game) error
+	autosaveError error
+
+	// The log of the actions of the player, nil if they aren't logged, see
+	// @StartLog.
+	moves *MoveLog
+}
+
+// Returns a new game of the given sudoku with an empty history. The timer
+// starts right away with the system clock, see @SetClock.
+func NewGame(sudoku Sudoku) *Game {
+	game := &Game{sudoku: sudoku, clock: systemClock{}}
+	game.started = game.clock.Now()
+
+	return game
+}
+
+// Returns a copy of the sudoku being played.
+func (game *Game) GetSudoku() Sudoku {
+	return game.sudoku
+}
+
+// Applies the move to the cell on the row x and column y and records it in the
+// history. Moves that don't change the cell are not recorded.
+func (game *Game) record(x, y int, move func() error) error {
+	change := cellChange{cell: Cell{x, y}}
+
+	if x >= 0 && x <= 8 && y >= 0 && y <= 8 {
+		change.beforeValue = game.sudoku.values[x][y]
+		change.beforeCandidates = game.sudoku.candidates[x][y]
+	}
+
+	if err := move(); err != nil {
+		return err
+	}
+
+	change.afterValue = game.sudoku.values[x][y]
+	change.afterCandidates = game.sudoku.candidates[x][y]
+
+	if change.beforeValue == change.afterValue && change.beforeCandidates == change.afterCandidates {
+		return nil
+	}
+
+	game.redo = nil
+	game.logChange(change)
+
+	if game.grouping > 0 {
+		game.group = append(game.group, change)
+	} else {
+		game.undo = append(game.undo, []cellChange{change})
+		game.moved()
+	}
+
+	return nil
+}
+
+// Writes a value on the row x and column y, see @SetValue. If the value is not
+// the one of the solution it counts as a mistake, unless the cell already had
+// it, and once the mistake limit is reached no more values can be written.
+func (game *Game) SetValue(x, y, val int) error {
+	if game.IsLost() {
+		return errors.New("Sudoku: Mistake limit reached.")
+	}
+
+	// The mistake is counted before the move is recorded, so the autosave
+	// sees it.
+	return game.record(x, y, func() error {
+		before, _ := game.sudoku.GetValue(x, y)
+
+		if err := game.sudoku.SetValue(x, y, val); err != nil {
+			return err
+		}
+
+		// Puzzles without a unique solution can't count mistakes.
+		if solution, err := game.sudoku.uniqueSolution(); err == nil && solution[x][y] != val && before != val {
+			game.mistakes++
+		}
+
+		if game.IsLost() {
+			game.Pause()
+		}
+
+		return nil
+	})
+}
+
+// Sets the number of mistakes which ends the game, like 3 for three strikes.
+// A limit of 0 allows any number of mistakes.
+func (game *Game) SetMistakeLimit(limit int) {
+	game.mistakeLimit = limit
+}
+
+// Returns the number of wrong values entered so far. Undoing or fixing a
+// mistake doesn't take it back.
+func (game *Game) GetMistakes() int {
+	return game.mistakes
+}
+
+// Returns true if the mistake limit was reached.
+func (game *Game) IsLost() bool {
+	return game.mistakeLimit > 0 && game.mistakes >= game.mistakeLimit
+}
+
+// Erases the value on the row x and column y, see @ClearValue.
+func (game *Game) ClearValue(x, y int) error {
+	return game.record(x, y, func() error {
+		return game.sudoku.ClearValue(x, y)
+	})
+}
+
+// Erases every value and candidate, see @Reset. This is undone as a single
+// step.
+func (game *Game) Reset() {
+	game.Group(func() error {
+		for i := 0; i < 9; i++ {
+			for j := 0; j < 9; j++ {
+				game.record(i, j, func() error {
+					if game.sudoku.initialValues[i][j] == 0 {
+						game.sudoku.values[i][j] = 0
+						game.sudoku.candidates[i][j] = 0
+					}
+
+					return nil
+				})
+			}
+		}
+
+		return nil
+	})
+}
+
+// Adds or removes a candidate on the row x and column y, see
+// @ToggleCandidate.
+func (game *Game) ToggleCandidate(x, y, val int) error {
+	return game.record(x, y, func() error {
+		return game.sudoku.ToggleCandidate(x, y, val)
+	})
+}
+
+// Replaces the candidates on the row x and column y, see @SetCandidates.
+func (game *Game) SetCandidates(x, y int, vals []int) error {
+	return game.record(x, y, func() error {
+		return game.sudoku.SetCandidates(x, y, vals)
+	})
+}
+
+// Sets the candidates of every empty cell to the values which don't repeat a
+// value of its row, column or block. This is undone as a single step.
+func (game *Game) FillCandidates() {
+	game.Group(func() error {
+		for i := 0; i < 9; i++ {
+			for j := 0; j < 9; j++ {
+				if game.sudoku.values[i][j] == 0 {
+					game.SetCandidates(i, j, maskDigits(game.sudoku.possibleMask(i, j)))
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Runs after every step added to the history, or undone or redone.
+func (game *Game) moved() {
+	game.checkComplete()
+
+	if game.autosave != nil {
+		game.autosaveError = game.autosave(game)
+	}
+}
+
+// Group runs fn, and all the moves made by it are undone and redone as a
+// single step. Groups can be nested, in which case the outermost one makes the
+// step. The moves are kept even if fn returns an error.
+func (game *Game) Group(fn func() error) error {
+	game.grouping++
+	err := fn()
+	game.grouping--
+
+	if game.grouping == 0 && len(game.group) > 0 {
+		game.undo = append(game.undo, game.group)
+		game.group = nil
+		game.moved()
+	}
+
+	return err
+}
+
+// Returns true if there is a step to undo.
+func (game *Game) CanUndo() bool {
+	return len(game.undo) > 0
+}
+
+// Returns true if there is a step to redo.
+func (game *Game) CanRedo() bool {
+	return len(game.redo) > 0
+}
+
+// Reverts the last step of the history. Returns false if there was nothing to
+// undo.
+func (game *Game) Undo() bool {
+	if len(game.undo) == 0 {
+		return false
+	}
+
+	step := game.undo[len(game.undo)-1]
+	game.undo = game.undo[:len(game.undo)-1]
+
+	for k := len(step) - 1; k >= 0; k-- {
+		change := step[k]
+		game.sudoku.values[change.cell.Row][change.cell.Column] = change.beforeValue
+		game.sudoku.candidates[change.cell.Row][change.cell.Column] = change.beforeCandidates
+		game.logChange(change.inverse())
+	}
+
+	game.redo = append(game.redo, step)
+	game.moved()
+
+	return true
+}
+
+// Applies again the last step undone. Returns false if there was nothing to
+// redo.
+func (game *Game) Redo() bool {
+	if len(game.redo) == 0 {
+		return false
+	}
+
+	step := game.redo[len(game.redo)-1]
+	game.redo = game.redo[:len(game.redo)-1]
+
+	for _, change := range step {
+		game.sudoku.values[change.cell.Row][change.cell.Column] = change.afterValue
+		game.sudoku.candidates[change.cell.Row][change.cell.Column] = change.afterCandidates
+		game.logChange(change)
+	}
+
+	game.undo = append(game.undo, step)
+	game.moved()
+
+	return true
+}
@@ -0,0 +1,156 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+func TestUndoRedo(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	game := NewGame(sudoku)
+
+	if game.CanUndo() || game.Undo() || game.Redo() {
+		t.Errorf("Sudoku: New game has history.")
+	}
+
+	game.SetValue(0, 2, 4)
+	game.SetValue(0, 2, 1)
+	game.ClearValue(0, 2)
+	game.ToggleCandidate(0, 3, 6)
+
+	// Invalid moves and moves without effect are not recorded.
+	game.SetValue(0, 0, 1)
+	game.SetValue(9, 0, 1)
+	game.ClearValue(0, 2)
+
+	expected := []struct {
+		value      int
+		candidates int
+	}{
+		{0, 0}, // After undoing the candidate.
+		{1, 0}, // After undoing the erasure.
+		{4, 0},
+		{0, 0},
+	}
+
+	for k, step := range expected {
+		if !game.Undo() {
+			t.Fatalf("Sudoku: Can't undo step %d", k)
+		}
+
+		current := game.GetSudoku()
+		val, _ := current.GetValue(0, 2)
+		candidates, _ := current.GetCandidates(0, 3)
+
+		if val != step.value || len(candidates) != step.candidates {
+			t.Errorf("Sudoku: After undoing %d steps (0, 2) is %d with candidates %v", k+1, val, candidates)
+		}
+	}
+
+	if game.Undo() {
+		t.Errorf("Sudoku: Can undo past the start of the game.")
+	}
+
+	// Redo everything.
+	for game.Redo() {
+	}
+
+	current := game.GetSudoku()
+	if val, _ := current.GetValue(0, 2); val != 0 {
+		t.Errorf("Sudoku: After redoing (0, 2) should be empty but is %d", val)
+	}
+
+	if candidates, _ := current.GetCandidates(0, 3); len(candidates) != 1 || candidates[0] != 6 {
+		t.Errorf("Sudoku: After redoing (0, 3) should have candidate 6 but has %v", candidates)
+	}
+
+	// A new move clears the redo history.
+	game.Undo()
+	game.SetValue(8, 0, 3)
+
+	if game.CanRedo() {
+		t.Errorf("Sudoku: Can redo after a new move.")
+	}
+}
+
+func TestFillCandidates(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	game := NewGame(sudoku)
+
+	game.SetValue(0, 2, 4)
+	game.FillCandidates()
+
+	current := game.GetSudoku()
+
+	if candidates, _ := current.GetCandidates(0, 3); len(candidates) != 2 || candidates[0] != 2 || candidates[1] != 6 {
+		t.Errorf("Sudoku: Candidates of (0, 3) should be [2 6] but are %v", candidates)
+	}
+
+	// Filling the candidates is undone in a single step.
+	game.Undo()
+	current = game.GetSudoku()
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if candidates, _ := current.GetCandidates(i, j); len(candidates) != 0 {
+				t.Errorf("Sudoku: Candidates of (%d, %d) were not undone: %v", i, j, candidates)
+			}
+		}
+	}
+
+	if val, _ := current.GetValue(0, 2); val != 4 {
+		t.Errorf("Sudoku: Undoing the candidates undid a value.")
+	}
+}
+
+func TestGameReset(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	game := NewGame(sudoku)
+
+	game.SetValue(0, 2, 4)
+	game.ToggleCandidate(0, 3, 6)
+	game.Reset()
+
+	if current := game.GetSudoku(); current.FilledCount() != sudoku.FilledCount() {
+		t.Errorf("Sudoku: Reset left %d filled cells", current.FilledCount())
+	}
+
+	// Resetting is undone in a single step.
+	game.Undo()
+
+	current := game.GetSudoku()
+	val, _ := current.GetValue(0, 2)
+	candidates, _ := current.GetCandidates(0, 3)
+
+	if val != 4 || len(candidates) != 1 {
+		t.Errorf("Sudoku: Undoing the reset didn't restore the moves.")
+	}
+}
+
+func TestMistakeLimit(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	game := NewGame(sudoku)
+	game.SetMistakeLimit(3)
+
+	// The solution has a 4 on (0, 2).
+	game.SetValue(0, 2, 4)
+	game.SetValue(0, 2, 1)
+	game.Undo()
+	game.SetValue(0, 2, 2)
+
+	// Writing the same wrong value again is not another mistake.
+	game.SetValue(0, 2, 2)
+
+	if game.GetMistakes() != 2 || game.IsLost() {
+		t.Errorf("Sudoku: Should have 2 mistakes but has %d", game.GetMistakes())
+	}
+
+	game.SetValue(0, 3, 1)
+
+	if !game.IsLost() {
+		t.Errorf("Sudoku: Game should be lost after 3 mistakes.")
+	}
+
+	if err := game.SetValue(0, 2, 4); err == nil {
+		t.Errorf("Sudoku: Can write values after losing the game.")
+	}
+}
@@ -0,0 +1,190 @@
+package sudoku
+
+import (
+	"context"   // Cancelling generation.
+	"errors"    // Error handling.
+	"math/rand" // Random puzzles.
+)
+
+// The symmetries of the givens of a generated puzzle.
+const (
+	// The givens can be anywhere.
+	NoSymmetry = "none"
+
+	// The grid looks the same after turning it half a turn.
+	Rotational = "rotational"
+
+	// The grid looks the same on a mirror placed on its central column.
+	Mirror = "mirror"
+
+	// The grid looks the same on a mirror placed on its main diagonal.
+	Diagonal = "diagonal"
+)
+
+// The number of full grids tried by @Generate before giving up on a
+// difficulty.
+const generateAttempts = 1000
+
+// Generator makes random puzzles. Two generators with the same seed make the
+// same puzzles in the same order.
+type Generator struct {
+	random *rand.Rand
+}
+
+// Returns a generator of puzzles from the given seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand.New(rand.NewSource(seed))}
+}
+
+// Returns the cells which must be given or empty together with the cell on
+// the row x and column y to keep the symmetry, including the cell itself.
+func symmetricCells(symmetry string, x, y int) []Cell {
+	cells := []Cell{{x, y}}
+
+	var other Cell
+
+	switch symmetry {
+	case Rotational:
+		other = Cell{8 - x, 8 - y}
+	case Mirror:
+		other = Cell{x, 8 - y}
+	case Diagonal:
+		other = Cell{y, x}
+	default:
+		return cells
+	}
+
+	if other != cells[0] {
+		cells = append(cells, other)
+	}
+
+	return cells
+}
+
+// Returns a sudoku whose initial values are the given grid.
+func puzzleFromGrid(grid [9][9]int) Sudoku {
+	return Sudoku{values: grid, initialValues: grid}
+}
+
+// Returns a random full grid.
+func (generator *Generator) fullGrid() [9][9]int {
+	var empty Sudoku
+
+	s, _ := newSolver(&empty, [9][9]int{})
+	s.limit = 1
+	s.random = generator.random
+	s.search()
+
+	return s.first
+}
+
+// Generate returns a random puzzle with a unique solution of the given
+// difficulty, see @Rate, whose givens keep the given symmetry. The givens are
+// removed one symmetric group at a time for as long as the puzzle keeps a
+// unique solution and doesn't get harder than the difficulty, so the puzzle
+// is minimal for its symmetry. An error is returned if the difficulty or the
+// symmetry are unknown, or no puzzle of the difficulty was found.
+func (generator *Generator) Generate(difficulty, symmetry string) (Sudoku, error) {
+	return generator.GenerateContext(context.Background(), difficulty, symmetry)
+}
+
+// GenerateContext is @Generate stopping once the context is done, in which
+// case the error of the context is returned.
+func (generator *Generator) GenerateContext(ctx context.Context, difficulty, symmetry string) (Sudoku, error) {
+	target := difficultyIndex(difficulty)
+
+	if target < 0 {
+		return Sudoku{}, errors.New("Sudoku: Unknown difficulty.")
+	}
+
+	switch symmetry {
+	case NoSymmetry, Rotational, Mirror, Diagonal:
+	default:
+		return Sudoku{}, errors.New("Sudoku: Unknown symmetry.")
+	}
+
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		grid := generator.fullGrid()
+
+		for _, k := range generator.random.Perm(81) {
+			if err := ctx.Err(); err != nil {
+				return Sudoku{}, err
+			}
+
+			if grid[k/9][k%9] == 0 {
+				continue
+			}
+
+			removed := grid
+			for _, cell := range symmetricCells(symmetry, k/9, k%9) {
+				removed[cell.Row][cell.Column] = 0
+			}
+
+			puzzle := puzzleFromGrid(removed)
+			rating, err := puzzle.Rate()
+
+			if err == nil && difficultyIndex(rating.Difficulty) <= target {
+				grid = removed
+			}
+		}
+
+		puzzle := puzzleFromGrid(grid)
+
+		if rating, _ := puzzle.Rate(); rating.Difficulty == difficulty {
+			puzzle.metadata.Difficulty = difficulty
+			return puzzle, nil
+		}
+	}
+
+	return Sudoku{}, errors.New("Sudoku: No puzzle of the difficulty was found.")
+}
+
+// Validation is the result of @Validate. Solutions is 2 for puzzles with more
+// than one solution.
+type Validation struct {
+	Puzzle    string     `json:"puzzle"`
+	Solutions int        `json:"solutions"`
+	Minimal   bool       `json:"minimal"`
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+	Valid     bool       `json:"valid"`
+}
+
+// Checks the uniqueness, minimality and conflicts of the puzzle given by the
+// initial values. A valid puzzle has a unique solution and no conflicts, even
+// if it's not minimal.
+func (sudoku *Sudoku) Validate() Validation {
+	result := Validation{Puzzle: sudoku.GivensLine(), Conflicts: sudoku.Conflicts()}
+
+	result.Solutions, _ = sudoku.countSolutions(2)
+	result.Minimal, _ = sudoku.IsMinimal()
+	result.Valid = result.Solutions == 1 && len(result.Conflicts) == 0
+
+	return result
+}
+
+// Returns true if the puzzle given by the initial values has a unique
+// solution which is lost by removing any of the givens. An error is returned
+// if the puzzle doesn't have a unique solution.
+func (sudoku *Sudoku) IsMinimal() (bool, error) {
+	if _, err := sudoku.uniqueSolution(); err != nil {
+		return false, err
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if sudoku.initialValues[i][j] == 0 {
+				continue
+			}
+
+			removed := *sudoku
+			removed.initialValues[i][j] = 0
+			removed.forgetSolution()
+
+			if count, _ := removed.countSolutions(2); count == 1 {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
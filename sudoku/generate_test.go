@@ -0,0 +1,72 @@
+package sudoku
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, difficulty := range []string{Easy, Medium, Hard} {
+		for _, symmetry := range []string{NoSymmetry, Rotational, Mirror, Diagonal} {
+			sudoku, err := NewGenerator(7).Generate(difficulty, symmetry)
+			if err != nil {
+				t.Fatalf("Sudoku: Can't generate %s puzzle with %s symmetry: %v", difficulty, symmetry, err)
+			}
+
+			if rating, err := sudoku.Rate(); err != nil || rating.Difficulty != difficulty {
+				t.Errorf("Sudoku: Generated %s puzzle is rated %s (%v)", difficulty, rating.Difficulty, err)
+			}
+
+			for i := 0; i < 9; i++ {
+				for j := 0; j < 9; j++ {
+					for _, cell := range symmetricCells(symmetry, i, j) {
+						if (sudoku.initialValues[i][j] == 0) != (sudoku.initialValues[cell.Row][cell.Column] == 0) {
+							t.Errorf("Sudoku: Puzzle breaks the %s symmetry on (%d, %d):\n%v", symmetry, i, j, sudoku.ToString())
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// The same seed makes the same puzzles.
+	a, b := NewGenerator(42), NewGenerator(42)
+
+	for k := 0; k < 3; k++ {
+		first, _ := a.Generate(Medium, Rotational)
+		second, _ := b.Generate(Medium, Rotational)
+
+		if first.initialValues != second.initialValues {
+			t.Errorf("Sudoku: Puzzle %d of the same seed differs", k)
+		}
+	}
+
+	if _, err := a.Generate("Impossible", NoSymmetry); err == nil {
+		t.Errorf("Sudoku: Generated a puzzle of unknown difficulty.")
+	}
+
+	if _, err := a.Generate(Easy, "spiral"); err == nil {
+		t.Errorf("Sudoku: Generated a puzzle of unknown symmetry.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := a.GenerateContext(ctx, Easy, Rotational); err != context.Canceled {
+		t.Errorf("Sudoku: Generation wasn't cancelled: %v", err)
+	}
+}
+
+func TestIsMinimal(t *testing.T) {
+	sudoku, _ := NewGenerator(1).Generate(Hard, NoSymmetry)
+
+	if minimal, err := sudoku.IsMinimal(); err != nil || !minimal {
+		t.Errorf("Sudoku: Generated puzzle is not minimal (%v)", err)
+	}
+
+	sudoku, _ = Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	if minimal, err := sudoku.IsMinimal(); err != nil || minimal {
+		t.Errorf("Sudoku: Puzzle with redundant givens is minimal (%v)", err)
+	}
+}
@@ -0,0 +1,425 @@
+package sudoku
+
+import (
+	"errors"  // Error handling.
+	"fmt"     // String formatting.
+	"strings" // String manipulation.
+)
+
+// The techniques of the logical solver, from the easiest to the hardest.
+const (
+	FullHouse    = "Full House"
+	NakedSingle  = "Naked Single"
+	HiddenSingle = "Hidden Single"
+	Pointing     = "Pointing"
+	Claiming     = "Claiming"
+	NakedPair    = "Naked Pair"
+	HiddenPair   = "Hidden Pair"
+)
+
+// The logical techniques in the order they are tried.
+var techniques = []func(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool){
+	findFullHouse,
+	findNakedSingle,
+	findHiddenSingle,
+	findPointing,
+	findClaiming,
+	findNakedPair,
+	findHiddenPair,
+}
+
+// Candidate is a value as one of the candidates of a cell.
+type Candidate struct {
+	Cell  Cell `json:"cell"`
+	Value int  `json:"value"`
+}
+
+// Hint is a logical deduction on the current values of a sudoku, which either
+// places a value or removes some candidates.
+type Hint struct {
+	// The technique used, like NakedSingle.
+	Technique string `json:"technique"`
+
+	// The unit where the deduction happens.
+	Unit  string `json:"unit"`
+	Index int    `json:"index"`
+
+	// The cells that make the deduction possible.
+	Cells []Cell `json:"cells"`
+
+	// The value placed by the hint, nil if it only removes candidates.
+	Placement *Candidate `json:"placement,omitempty"`
+
+	// The candidates removed by the hint.
+	Eliminations []Candidate `json:"eliminations,omitempty"`
+}
+
+// Returns the name of the unit as read by a player, like "row 1".
+func unitName(unit string, index int) string {
+	return fmt.Sprintf("%s %d", unit, index+1)
+}
+
+// Returns the text of the hint, revealing more of it as the level grows:
+// level 1 names the technique and the unit, level 2 also names the cells
+// involved, and level 3 also tells the placement or the eliminations.
+func (hint *Hint) Text(level int) string {
+	text := fmt.Sprintf("Look for a %s in %s.", hint.Technique, unitName(hint.Unit, hint.Index))
+
+	if level < 2 {
+		return text
+	}
+
+	cells := make([]string, len(hint.Cells))
+	for k, cell := range hint.Cells {
+		cells[k] = cell.String()
+	}
+
+	text += fmt.Sprintf(" Look at %s.", strings.Join(cells, ", "))
+
+	if level < 3 {
+		return text
+	}
+
+	if hint.Placement != nil {
+		return text + fmt.Sprintf(" Place %d in %s.", hint.Placement.Value, hint.Placement.Cell)
+	}
+
+	removed := make([]string, len(hint.Eliminations))
+	for k, elimination := range hint.Eliminations {
+		removed[k] = fmt.Sprintf("%d from %s", elimination.Value, elimination.Cell)
+	}
+
+	return text + fmt.Sprintf(" Remove %s.", strings.Join(removed, ", "))
+}
+
+// Returns the cells a renderer should highlight for the hint at the given
+// level: the unit at level 1, the cells involved at level 2, and also the
+// cells changed by the hint at level 3.
+func (hint *Hint) Highlights(level int) []Cell {
+	if level < 2 {
+		cells := unitCells(hint.Unit, hint.Index)
+		return cells[:]
+	}
+
+	cells := append([]Cell(nil), hint.Cells...)
+
+	if level < 3 {
+		return cells
+	}
+
+	seen := map[Cell]bool{}
+	for _, cell := range cells {
+		seen[cell] = true
+	}
+
+	if hint.Placement != nil && !seen[hint.Placement.Cell] {
+		cells = append(cells, hint.Placement.Cell)
+	}
+
+	for _, elimination := range hint.Eliminations {
+		if !seen[elimination.Cell] {
+			seen[elimination.Cell] = true
+			cells = append(cells, elimination.Cell)
+		}
+	}
+
+	return cells
+}
+
+// Returns the candidates the logical solver starts from. These are the values
+// that don't repeat a value of the row, column or block of each empty cell nor
+// break one of its constraints, narrowed down by the pencil marks of the
+// player unless they would remove the solution. Marks which leave none of
+// these values are ignored, as are marks of puzzles without a unique solution.
+func (sudoku *Sudoku) logicCandidates() [9][9]uint16 {
+	var candidates [9][9]uint16
+
+	solution, _ := sudoku.uniqueSolution()
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if sudoku.values[i][j] != 0 {
+				continue
+			}
+
+			candidates[i][j] = sudoku.allowedMask(i, j)
+
+			marks := sudoku.candidates[i][j] & candidates[i][j]
+
+			if marks != 0 && solution != nil && marks&(1<<solution[i][j]) != 0 {
+				candidates[i][j] = marks
+			}
+		}
+	}
+
+	return candidates
+}
+
+// Returns the easiest deduction on the given values and candidates.
+func findStep(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, find := range techniques {
+		if hint, ok := find(values, candidates); ok {
+			return hint, true
+		}
+	}
+
+	return Hint{}, false
+}
+
+// NextHint returns the easiest deduction available on the current values of
+// the sudoku. An error is returned if the sudoku is complete, if it has
+// conflicts, or if none of the techniques of the logical solver applies.
+func (sudoku *Sudoku) NextHint() (Hint, error) {
+	if len(sudoku.EmptyCells()) == 0 {
+		return Hint{}, errors.New("Sudoku: The sudoku is already filled.")
+	}
+
+	if len(sudoku.Conflicts()) > 0 {
+		return Hint{}, errors.New("Sudoku: The sudoku has conflicts.")
+	}
+
+	values := sudoku.values
+	candidates := sudoku.logicCandidates()
+
+	hint, ok := findStep(&values, &candidates)
+	if !ok {
+		return Hint{}, errors.New("Sudoku: No logical step found.")
+	}
+
+	return hint, nil
+}
+
+// Returns the cells of the unit which have val as a candidate, in the order of
+// the unit.
+func cellsWith(cells [9]Cell, candidates *[9][9]uint16, val int) []Cell {
+	var result []Cell
+
+	for _, cell := range cells {
+		if candidates[cell.Row][cell.Column]&(1<<val) != 0 {
+			result = append(result, cell)
+		}
+	}
+
+	return result
+}
+
+// Returns the eliminations of the values of the mask from the cells, skipping
+// the cells in keep and the candidates already removed.
+func eliminate(cells []Cell, keep []Cell, mask uint16, candidates *[9][9]uint16) []Candidate {
+	var result []Candidate
+
+	kept := map[Cell]bool{}
+	for _, cell := range keep {
+		kept[cell] = true
+	}
+
+	for _, cell := range cells {
+		if kept[cell] {
+			continue
+		}
+
+		for _, val := range maskDigits(candidates[cell.Row][cell.Column] & mask) {
+			result = append(result, Candidate{cell, val})
+		}
+	}
+
+	return result
+}
+
+// The units in the order a player usually scans them.
+var allUnits = []string{BlockUnit, RowUnit, ColumnUnit}
+
+// A unit with a single empty cell, which takes the missing value.
+func findFullHouse(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, unit := range allUnits {
+		for index := 0; index < 9; index++ {
+			var empty []Cell
+			var used uint16
+
+			for _, cell := range unitCells(unit, index) {
+				if values[cell.Row][cell.Column] == 0 {
+					empty = append(empty, cell)
+				}
+
+				used |= 1 << values[cell.Row][cell.Column]
+			}
+
+			if len(empty) == 1 {
+				missing := maskDigits(^used & 0x3FE)
+
+				if len(missing) == 1 {
+					return Hint{FullHouse, unit, index, empty, &Candidate{empty[0], missing[0]}, nil}, true
+				}
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// A cell with a single candidate.
+func findNakedSingle(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if digits := maskDigits(candidates[i][j]); values[i][j] == 0 && len(digits) == 1 {
+				cell := Cell{i, j}
+				return Hint{NakedSingle, BlockUnit, (i/3)*3 + j/3, []Cell{cell}, &Candidate{cell, digits[0]}, nil}, true
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// A value which is a candidate of a single cell of a unit.
+func findHiddenSingle(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, unit := range allUnits {
+		for index := 0; index < 9; index++ {
+			cells := unitCells(unit, index)
+
+			for val := 1; val <= 9; val++ {
+				if with := cellsWith(cells, candidates, val); len(with) == 1 {
+					return Hint{HiddenSingle, unit, index, with, &Candidate{with[0], val}, nil}, true
+				}
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// The candidates of a value in a block all lie on a single row or column, so
+// the value can be removed from the rest of that row or column.
+func findPointing(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for block := 0; block < 9; block++ {
+		for val := 1; val <= 9; val++ {
+			with := cellsWith(unitCells(BlockUnit, block), candidates, val)
+
+			if len(with) < 2 {
+				continue
+			}
+
+			sameRow, sameColumn := true, true
+			for _, cell := range with {
+				sameRow = sameRow && cell.Row == with[0].Row
+				sameColumn = sameColumn && cell.Column == with[0].Column
+			}
+
+			var line [9]Cell
+			if sameRow {
+				line = unitCells(RowUnit, with[0].Row)
+			} else if sameColumn {
+				line = unitCells(ColumnUnit, with[0].Column)
+			} else {
+				continue
+			}
+
+			if eliminations := eliminate(line[:], with, 1<<val, candidates); len(eliminations) > 0 {
+				return Hint{Pointing, BlockUnit, block, with, nil, eliminations}, true
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// The candidates of a value in a row or column all lie on a single block, so
+// the value can be removed from the rest of that block.
+func findClaiming(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, unit := range []string{RowUnit, ColumnUnit} {
+		for index := 0; index < 9; index++ {
+			for val := 1; val <= 9; val++ {
+				with := cellsWith(unitCells(unit, index), candidates, val)
+
+				if len(with) < 2 {
+					continue
+				}
+
+				block := (with[0].Row/3)*3 + with[0].Column/3
+				same := true
+
+				for _, cell := range with {
+					same = same && (cell.Row/3)*3+cell.Column/3 == block
+				}
+
+				if !same {
+					continue
+				}
+
+				cells := unitCells(BlockUnit, block)
+
+				if eliminations := eliminate(cells[:], with, 1<<val, candidates); len(eliminations) > 0 {
+					return Hint{Claiming, unit, index, with, nil, eliminations}, true
+				}
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// Two cells of a unit with the same two candidates, which can be removed from
+// the other cells of the unit.
+func findNakedPair(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, unit := range allUnits {
+		for index := 0; index < 9; index++ {
+			cells := unitCells(unit, index)
+
+			for a := 0; a < 9; a++ {
+				mask := candidates[cells[a].Row][cells[a].Column]
+
+				if len(maskDigits(mask)) != 2 {
+					continue
+				}
+
+				for b := a + 1; b < 9; b++ {
+					if candidates[cells[b].Row][cells[b].Column] != mask {
+						continue
+					}
+
+					pair := []Cell{cells[a], cells[b]}
+
+					if eliminations := eliminate(cells[:], pair, mask, candidates); len(eliminations) > 0 {
+						return Hint{NakedPair, unit, index, pair, nil, eliminations}, true
+					}
+				}
+			}
+		}
+	}
+
+	return Hint{}, false
+}
+
+// Two values which are candidates of the same two cells of a unit and no
+// other, so the other candidates of those cells can be removed.
+func findHiddenPair(values *[9][9]int, candidates *[9][9]uint16) (Hint, bool) {
+	for _, unit := range allUnits {
+		for index := 0; index < 9; index++ {
+			cells := unitCells(unit, index)
+
+			for v1 := 1; v1 <= 9; v1++ {
+				with := cellsWith(cells, candidates, v1)
+
+				if len(with) != 2 {
+					continue
+				}
+
+				for v2 := v1 + 1; v2 <= 9; v2++ {
+					other := cellsWith(cells, candidates, v2)
+
+					if len(other) != 2 || other[0] != with[0] || other[1] != with[1] {
+						continue
+					}
+
+					mask := ^uint16(1<<v1|1<<v2) & 0x3FE
+
+					if eliminations := eliminate(with, nil, mask, candidates); len(eliminations) > 0 {
+						return Hint{HiddenPair, unit, index, with, nil, eliminations}, true
+					}
+				}
+			}
+		}
+	}
+
+	return Hint{}, false
+}
@@ -0,0 +1,132 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+// Applies the hint to the sudoku, keeping the eliminations as pencil marks.
+func applyHint(sudoku *Sudoku, hint Hint) {
+	if hint.Placement != nil {
+		sudoku.SetValue(hint.Placement.Cell.Row, hint.Placement.Cell.Column, hint.Placement.Value)
+		return
+	}
+
+	candidates := sudoku.logicCandidates()
+
+	for _, elimination := range hint.Eliminations {
+		candidates[elimination.Cell.Row][elimination.Cell.Column] &^= 1 << elimination.Value
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			sudoku.SetCandidates(i, j, maskDigits(candidates[i][j]))
+		}
+	}
+}
+
+func TestNextHint(t *testing.T) {
+	puzzles := []string{
+		"530070000600195000098000060800060003400803001700020006060000280000419005000080079",
+		"000000010400000000020000000000050407008000300001090000300400200050100000000806000",
+	}
+
+	for _, puzzle := range puzzles {
+		sudoku, _ := Parse(puzzle)
+		solution, _ := sudoku.uniqueSolution()
+		techniques := map[string]bool{}
+
+		for len(sudoku.EmptyCells()) > 0 {
+			hint, err := sudoku.NextHint()
+			if err != nil {
+				break
+			}
+
+			techniques[hint.Technique] = true
+
+			if p := hint.Placement; p != nil && solution[p.Cell.Row][p.Cell.Column] != p.Value {
+				t.Fatalf("Sudoku: Hint places a wrong value: %s", hint.Text(3))
+			}
+
+			for _, e := range hint.Eliminations {
+				if solution[e.Cell.Row][e.Cell.Column] == e.Value {
+					t.Fatalf("Sudoku: Hint removes the solution: %s", hint.Text(3))
+				}
+			}
+
+			applyHint(&sudoku, hint)
+		}
+
+		if puzzle == puzzles[0] && !sudoku.IsComplete() {
+			t.Errorf("Sudoku: Easy puzzle can't be solved with hints:\n%v", sudoku.ToString())
+		}
+
+		if puzzle == puzzles[0] && (!techniques[NakedSingle] || techniques[NakedPair]) {
+			t.Errorf("Sudoku: Easy puzzle used the techniques %v", techniques)
+		}
+	}
+
+	// No hints for complete sudokus or sudokus with conflicts.
+	sudoku, _ := Parse(puzzles[0])
+	sudoku.SetValue(0, 2, 5)
+
+	if _, err := sudoku.NextHint(); err == nil {
+		t.Errorf("Sudoku: Returns hint for a sudoku with conflicts.")
+	}
+}
+
+func TestLogicCandidates(t *testing.T) {
+	// A wrong pencil mark on (0, 2), whose solution is 4, is ignored.
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	sudoku.SetCandidates(0, 2, []int{1, 2})
+
+	if candidates := sudoku.logicCandidates(); candidates[0][2]&(1<<4) == 0 {
+		t.Errorf("Sudoku: Candidates of (0, 2) are %v", maskDigits(candidates[0][2]))
+	}
+
+	// Without a unique solution the marks can't be checked, and the cage
+	// leaves only 1, 2 and 3.
+	var empty Sudoku
+	empty.AddConstraint(Constraint{Cage, []Cell{{0, 0}, {0, 1}}, 3})
+	empty.SetCandidates(0, 0, []int{2, 9})
+
+	if candidates := empty.logicCandidates(); candidates[0][0] != 1<<1|1<<2|1<<3 {
+		t.Errorf("Sudoku: Candidates of (0, 0) are %v", maskDigits(candidates[0][0]))
+	}
+}
+
+func TestHintText(t *testing.T) {
+	hint := Hint{
+		Technique:    Pointing,
+		Unit:         BlockUnit,
+		Index:        0,
+		Cells:        []Cell{{0, 0}, {0, 1}},
+		Eliminations: []Candidate{{Cell{0, 5}, 3}, {Cell{0, 7}, 3}},
+	}
+
+	levels := []string{
+		"Look for a Pointing in block 1.",
+		"Look for a Pointing in block 1. Look at r1c1, r1c2.",
+		"Look for a Pointing in block 1. Look at r1c1, r1c2. Remove 3 from r1c6, 3 from r1c8.",
+	}
+
+	for level, text := range levels {
+		if hint.Text(level+1) != text {
+			t.Errorf("Sudoku: Text of level %d should be %q but is %q", level+1, text, hint.Text(level+1))
+		}
+	}
+
+	if cells := hint.Highlights(1); len(cells) != 9 {
+		t.Errorf("Sudoku: Level 1 should highlight the block but highlights %v", cells)
+	}
+
+	if cells := hint.Highlights(3); len(cells) != 4 {
+		t.Errorf("Sudoku: Level 3 should highlight 4 cells but highlights %v", cells)
+	}
+
+	hint = Hint{Technique: NakedSingle, Unit: RowUnit, Index: 2, Cells: []Cell{{2, 2}}, Placement: &Candidate{Cell{2, 2}, 7}}
+
+	if !strings.HasSuffix(hint.Text(3), "Place 7 in r3c3.") {
+		t.Errorf("Sudoku: Unexpected text %q", hint.Text(3))
+	}
+}
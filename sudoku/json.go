@@ -0,0 +1,158 @@
+package sudoku
+
+import (
+	"encoding/json" // JSON encoding.
+	"errors"        // Error handling.
+	"fmt"           // Error formatting.
+	"strings"       // String manipulation.
+)
+
+// The version of the JSON schema written by @MarshalJSON. Every version ever
+// written must still be accepted by @UnmarshalJSON.
+const jsonVersion = 1
+
+// A sudoku is encoded in JSON as the following object.
+//
+//	{
+//	  "version": 1,
+//	  "givens": "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79",
+//	  "values": "534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+//	  "candidates": ["", "", "12", ...],
+//	  "constraints": [{"type": "cage", "cells": [{"row": 0, "column": 0}, ...], "value": 10}],
+//	  "metadata": {"author": "...", "difficulty": "..."}
+//	}
+//
+// "givens" and "values" are grids in the 81 character format of @Parse, where
+// "values" also contains the givens. "candidates" has the pencil marks of each
+// cell, in the same order as the grids, as a string of digits; it is omitted
+// when no cell has candidates. "constraints" lists the variant constraints as
+// described in @Constraint and is omitted when there are none. "metadata" holds
+// the fields of @Metadata and is omitted when empty. "version" is the version
+// of this schema.
+type jsonSudoku struct {
+	Version     int          `json:"version"`
+	Givens      string       `json:"givens"`
+	Values      string       `json:"values"`
+	Candidates  []string     `json:"candidates,omitempty"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+	Metadata    *Metadata    `json:"metadata,omitempty"`
+}
+
+// Returns the grid in the 81 character format, with '.' for empty cells.
+func gridString(grid [9][9]int) string {
+	var b strings.Builder
+
+	for _, row := range grid {
+		b.WriteString(sdkRow(row))
+	}
+
+	return b.String()
+}
+
+// Returns the values of the sudoku in the 81 character format of @Parse, with
+// '.' for empty cells.
+func (sudoku *Sudoku) Line() string {
+	return gridString(sudoku.values)
+}
+
+// Returns the initial values of the sudoku in the 81 character format of
+// @Parse, with '.' for empty cells.
+func (sudoku *Sudoku) GivensLine() string {
+	return gridString(sudoku.initialValues)
+}
+
+// MarshalJSON encodes the sudoku as described in @jsonSudoku.
+func (sudoku Sudoku) MarshalJSON() ([]byte, error) {
+	doc := jsonSudoku{
+		Version:     jsonVersion,
+		Givens:      gridString(sudoku.initialValues),
+		Values:      gridString(sudoku.values),
+		Constraints: sudoku.constraints,
+	}
+
+	if sudoku.candidates != [9][9]uint16{} {
+		doc.Candidates = make([]string, 81)
+
+		for i := 0; i < 9; i++ {
+			for j := 0; j < 9; j++ {
+				for _, val := range maskDigits(sudoku.candidates[i][j]) {
+					doc.Candidates[i*9+j] += string(rune('0' + val))
+				}
+			}
+		}
+	}
+
+	if sudoku.metadata != (Metadata{}) {
+		doc.Metadata = &sudoku.metadata
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a sudoku written by @MarshalJSON with the current or
+// any previous version of the schema. A missing version is read as version 1.
+func (sudoku *Sudoku) UnmarshalJSON(data []byte) error {
+	var doc jsonSudoku
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version > jsonVersion {
+		return fmt.Errorf("Sudoku: Unsupported JSON version %d.", doc.Version)
+	}
+
+	result, err := Parse(doc.Givens)
+	if err != nil {
+		return fmt.Errorf("Sudoku: Invalid givens: %w", err)
+	}
+
+	values, err := Parse(doc.Values)
+	if err != nil {
+		return fmt.Errorf("Sudoku: Invalid values: %w", err)
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			val := values.values[i][j]
+
+			if result.initialValues[i][j] == 0 {
+				if val != 0 {
+					result.SetValue(i, j, val)
+				}
+			} else if val != result.initialValues[i][j] {
+				return fmt.Errorf("Sudoku: Values overwrite initial value on (%d, %d).", i, j)
+			}
+		}
+	}
+
+	if doc.Candidates != nil && len(doc.Candidates) != 81 {
+		return errors.New("Sudoku: Expected 81 lists of candidates.")
+	}
+
+	for k, list := range doc.Candidates {
+		var vals []int
+
+		for _, r := range list {
+			vals = append(vals, int(r-'0'))
+		}
+
+		if err := result.SetCandidates(k/9, k%9, vals); err != nil {
+			return err
+		}
+	}
+
+	for _, constraint := range doc.Constraints {
+		if err := result.AddConstraint(constraint); err != nil {
+			return err
+		}
+	}
+
+	if doc.Metadata != nil {
+		result.metadata = *doc.Metadata
+	}
+
+	*sudoku = result
+
+	return nil
+}
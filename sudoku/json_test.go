@@ -0,0 +1,67 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	sudoku.SetValue(0, 2, 4)
+	sudoku.SetValue(8, 0, 3)
+	sudoku.SetCandidates(0, 3, []int{2, 6})
+	sudoku.SetMetadata(Metadata{Author: "John Doe", Difficulty: "Easy"})
+	sudoku.AddConstraint(Constraint{Cage, []Cell{{0, 2}, {0, 3}}, 6})
+
+	data, err := json.Marshal(sudoku)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't marshal sudoku: %v", err)
+	}
+
+	var other Sudoku
+	if err := json.Unmarshal(data, &other); err != nil {
+		t.Fatalf("Sudoku: Can't unmarshal %s: %v", data, err)
+	}
+
+	if !reflect.DeepEqual(other, sudoku) {
+		t.Errorf("Sudoku: Unmarshaled\n%v\ninstead of\n%v", other.ToString(), sudoku.ToString())
+	}
+
+	// Values must still be distinguished from the givens.
+	if other.SetValue(0, 2, 1) != nil || other.SetValue(0, 0, 1) == nil {
+		t.Errorf("Sudoku: Givens and values were mixed up in %s", data)
+	}
+
+	// Empty fields are omitted.
+	var empty Sudoku
+	data, _ = json.Marshal(empty)
+
+	if strings.Contains(string(data), "candidates") || strings.Contains(string(data), "metadata") {
+		t.Errorf("Sudoku: Empty fields were not omitted: %s", data)
+	}
+}
+
+func TestJSONVersions(t *testing.T) {
+	var sudoku Sudoku
+
+	givens := strings.Repeat(".", 80) + "1"
+
+	// Documents without version are read as version 1.
+	if err := json.Unmarshal([]byte(`{"givens": "`+givens+`", "values": "`+givens+`"}`), &sudoku); err != nil {
+		t.Errorf("Sudoku: Can't read document without version: %v", err)
+	}
+
+	// Documents from a newer version are rejected.
+	if err := json.Unmarshal([]byte(`{"version": 1000, "givens": "`+givens+`", "values": "`+givens+`"}`), &sudoku); err == nil {
+		t.Errorf("Sudoku: Accepts document from a newer version.")
+	}
+
+	// Values can't overwrite givens.
+	values := strings.Repeat(".", 80) + "2"
+	if err := json.Unmarshal([]byte(`{"version": 1, "givens": "`+givens+`", "values": "`+values+`"}`), &sudoku); err == nil {
+		t.Errorf("Sudoku: Values can overwrite givens.")
+	}
+}
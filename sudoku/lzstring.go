@@ -0,0 +1,305 @@
+package sudoku
+
+import (
+	"errors"        // Error handling.
+	"strings"       // String manipulation.
+	"unicode/utf16" // The strings are compressed as UTF-16 code units.
+)
+
+// The alphabet of the Base64 variant of lz-string, '=' is the padding.
+const lzAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// Writes the bits of a lz-string stream as Base64 characters.
+type lzWriter struct {
+	out      strings.Builder
+	val      int
+	position int
+}
+
+// Writes the n lowest bits of value, starting with the least significant one.
+func (w *lzWriter) writeBits(value, n int) {
+	for i := 0; i < n; i++ {
+		w.val = (w.val << 1) | (value & 1)
+		value >>= 1
+
+		if w.position == 5 {
+			w.position = 0
+			w.out.WriteByte(lzAlphabet[w.val])
+			w.val = 0
+		} else {
+			w.position++
+		}
+	}
+}
+
+// Returns the key of a sequence of code units in the dictionary.
+func lzKey(units []uint16) string {
+	b := make([]byte, 0, 2*len(units))
+
+	for _, u := range units {
+		b = append(b, byte(u>>8), byte(u))
+	}
+
+	return string(b)
+}
+
+// Compresses the string with the lz-string algorithm and returns it in its
+// Base64 form. This is the same output as compressToBase64 of the JavaScript
+// library, which is used by f-puzzles and SudokuPad to store puzzles in URLs.
+func lzCompressToBase64(s string) string {
+	var w lzWriter
+
+	units := utf16.Encode([]rune(s))
+	dictionary := map[string]int{}
+	toCreate := map[string]bool{}
+	enlargeIn := 2
+	dictSize := 3
+	numBits := 2
+
+	// Writes the code of the phrase, or its literal the first time it's used.
+	emit := func(phrase []uint16) {
+		key := lzKey(phrase)
+
+		if toCreate[key] {
+			if phrase[0] < 256 {
+				w.writeBits(0, numBits)
+				w.writeBits(int(phrase[0]), 8)
+			} else {
+				w.writeBits(1, numBits)
+				w.writeBits(int(phrase[0]), 16)
+			}
+
+			enlargeIn--
+			if enlargeIn == 0 {
+				enlargeIn = 1 << numBits
+				numBits++
+			}
+
+			delete(toCreate, key)
+		} else {
+			w.writeBits(dictionary[key], numBits)
+		}
+
+		enlargeIn--
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+
+	var phrase []uint16
+
+	for i := range units {
+		c := units[i : i+1]
+
+		if _, ok := dictionary[lzKey(c)]; !ok {
+			dictionary[lzKey(c)] = dictSize
+			dictSize++
+			toCreate[lzKey(c)] = true
+		}
+
+		next := append(append([]uint16{}, phrase...), c...)
+
+		if _, ok := dictionary[lzKey(next)]; ok {
+			phrase = next
+			continue
+		}
+
+		emit(phrase)
+
+		dictionary[lzKey(next)] = dictSize
+		dictSize++
+		phrase = c
+	}
+
+	if len(phrase) > 0 {
+		emit(phrase)
+	}
+
+	// Mark the end of the stream and flush the last character.
+	w.writeBits(2, numBits)
+
+	for {
+		w.val <<= 1
+
+		if w.position == 5 {
+			w.out.WriteByte(lzAlphabet[w.val])
+			break
+		}
+
+		w.position++
+	}
+
+	out := w.out.String()
+
+	if n := len(out) % 4; n != 0 {
+		out += strings.Repeat("=", 4-n)
+	}
+
+	return out
+}
+
+// Reads the bits of a lz-string stream from its Base64 characters.
+type lzReader struct {
+	in       string
+	index    int
+	val      int
+	position int
+}
+
+// Returns the value of the Base64 character on the index, characters past the
+// end of the input are read as zero.
+func (r *lzReader) next() (int, error) {
+	if r.index >= len(r.in) {
+		r.index++
+		return 0, nil
+	}
+
+	val := strings.IndexByte(lzAlphabet, r.in[r.index])
+	r.index++
+
+	if val < 0 {
+		return 0, errors.New("Sudoku: Invalid character in compressed data.")
+	}
+
+	return val, nil
+}
+
+// Reads n bits, the first one read is the least significant.
+func (r *lzReader) readBits(n int) (int, error) {
+	var err error
+
+	bits := 0
+
+	for i := 0; i < n; i++ {
+		if r.val&r.position != 0 {
+			bits |= 1 << i
+		}
+
+		r.position >>= 1
+
+		if r.position == 0 {
+			r.position = 32
+
+			if r.val, err = r.next(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return bits, nil
+}
+
+// Decompresses a string written by @lzCompressToBase64.
+func lzDecompressFromBase64(s string) (string, error) {
+	var err error
+
+	invalid := errors.New("Sudoku: Invalid compressed data.")
+
+	if s == "" {
+		return "", invalid
+	}
+
+	r := lzReader{in: s, position: 32}
+	if r.val, err = r.next(); err != nil {
+		return "", err
+	}
+
+	dictionary := [][]uint16{nil, nil, nil}
+	enlargeIn := 4
+	numBits := 3
+
+	// Reads a literal of the given size into the dictionary.
+	literal := func(size int) ([]uint16, error) {
+		bits, err := r.readBits(size)
+		if err != nil {
+			return nil, err
+		}
+
+		return []uint16{uint16(bits)}, nil
+	}
+
+	code, err := r.readBits(2)
+	if err != nil {
+		return "", err
+	}
+
+	var w []uint16
+
+	switch code {
+	case 0:
+		w, err = literal(8)
+	case 1:
+		w, err = literal(16)
+	case 2:
+		return "", nil
+	default:
+		return "", invalid
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	dictionary = append(dictionary, w)
+	result := append([]uint16{}, w...)
+
+	for {
+		if r.index > len(s) {
+			return "", invalid
+		}
+
+		code, err := r.readBits(numBits)
+		if err != nil {
+			return "", err
+		}
+
+		switch code {
+		case 0, 1:
+			size := 8
+			if code == 1 {
+				size = 16
+			}
+
+			entry, err := literal(size)
+			if err != nil {
+				return "", err
+			}
+
+			dictionary = append(dictionary, entry)
+			code = len(dictionary) - 1
+
+			enlargeIn--
+
+		case 2:
+			return string(utf16.Decode(result)), nil
+		}
+
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+
+		var entry []uint16
+
+		if code < len(dictionary) {
+			entry = dictionary[code]
+		} else if code == len(dictionary) {
+			entry = append(append([]uint16{}, w...), w[0])
+		} else {
+			return "", invalid
+		}
+
+		result = append(result, entry...)
+
+		dictionary = append(dictionary, append(append([]uint16{}, w...), entry[0]))
+		enlargeIn--
+
+		w = entry
+
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+}
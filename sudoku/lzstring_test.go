@@ -0,0 +1,43 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLZString(t *testing.T) {
+	inputs := []string{
+		"a",
+		"Hello, world!",
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		`{"size":9,"grid":[[{},{},{"value":3,"given":true}]]}`,
+		"Sudoku ☺ ünïcödé 数独 𝄞",
+		strings.Repeat("The quick brown fox jumps over the lazy dog. ", 50),
+	}
+
+	for _, input := range inputs {
+		compressed := lzCompressToBase64(input)
+
+		if len(compressed)%4 != 0 {
+			t.Errorf("Sudoku: Compressed data of %q is not padded: %s", input, compressed)
+		}
+
+		output, err := lzDecompressFromBase64(compressed)
+		if err != nil || output != input {
+			t.Errorf("Sudoku: Decompressed %q as %q (%v)", input, output, err)
+		}
+	}
+
+	// Output of compressToBase64 of the JavaScript lz-string, which f-puzzles
+	// uses.
+	if compressed := lzCompressToBase64("Hello, world!"); compressed != "BIUwNmD2A0AEDukBOYAmBCIA" {
+		t.Errorf("Sudoku: Compressed \"Hello, world!\" as %s", compressed)
+	}
+
+	// Invalid data must return an error instead of panicking.
+	for _, input := range []string{"", "!!!!", "AAAA", "////"} {
+		if _, err := lzDecompressFromBase64(input); err == nil {
+			t.Errorf("Sudoku: Accepts invalid data %q", input)
+		}
+	}
+}
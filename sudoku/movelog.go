@@ -0,0 +1,467 @@
+package sudoku
+
+import (
+	"encoding/json" // File format.
+	"errors"        // Error handling.
+	"fmt"           // String formatting.
+	"io"            // Writers.
+	"strconv"       // Parsing times.
+	"strings"       // String manipulation.
+	"time"          // Timestamps.
+)
+
+// The version of the move log written by @MoveLog.MarshalJSON.
+const moveLogVersion = 1
+
+// The kinds of @Action.
+const (
+	ActionPlace     = "place"
+	ActionClear     = "clear"
+	ActionCandidate = "candidate"
+	ActionHint      = "hint"
+)
+
+// The letter of every kind of action in the compact form of @Action.String.
+var actionCodes = map[string]byte{
+	ActionPlace:     'p',
+	ActionClear:     'c',
+	ActionCandidate: 't',
+	ActionHint:      'h',
+}
+
+// Action is something the player did on a game, at the given time of its
+// timer, see @Game.Elapsed.
+type Action struct {
+	Time time.Duration
+	Kind string
+	Cell Cell
+
+	// The value placed, the candidate toggled, or the value of the hint: the
+	// one it places, or the first candidate it removes. 0 for ActionClear.
+	Value int
+}
+
+// Returns the action in the compact form of a move log, the milliseconds of
+// the time, a colon, the letter of the kind, the row, the column and the
+// value, like "61500:p024" for a 4 placed on r1c3 after a minute and a half.
+func (action Action) String() string {
+	return fmt.Sprintf("%d:%c%d%d%d", action.Time.Milliseconds(), actionCodes[action.Kind],
+		action.Cell.Row, action.Cell.Column, action.Value)
+}
+
+// Returns the action written by @String.
+func parseAction(s string) (Action, error) {
+	colon := strings.IndexByte(s, ':')
+	if colon < 0 || len(s) != colon+5 {
+		return Action{}, fmt.Errorf("Sudoku: Invalid action %q.", s)
+	}
+
+	ms, err := strconv.ParseInt(s[:colon], 10, 64)
+	if err != nil || ms < 0 {
+		return Action{}, fmt.Errorf("Sudoku: Invalid action %q.", s)
+	}
+
+	action := Action{Time: time.Duration(ms) * time.Millisecond}
+
+	for kind, code := range actionCodes {
+		if s[colon+1] == code {
+			action.Kind = kind
+		}
+	}
+
+	digits := s[colon+2:]
+	if action.Kind == "" || digits[0] < '0' || digits[0] > '8' || digits[1] < '0' || digits[1] > '8' ||
+		digits[2] < '0' || digits[2] > '9' {
+		return Action{}, fmt.Errorf("Sudoku: Invalid action %q.", s)
+	}
+
+	action.Cell = Cell{int(digits[0] - '0'), int(digits[1] - '0')}
+	action.Value = int(digits[2] - '0')
+
+	if (action.Value == 0) != (action.Kind == ActionClear) {
+		return Action{}, fmt.Errorf("Sudoku: Invalid action %q.", s)
+	}
+
+	return action, nil
+}
+
+// Returns the action as read by a player, like "place 4 on r1c3".
+func (action Action) Text() string {
+	switch action.Kind {
+	case ActionClear:
+		return fmt.Sprintf("clear %v", action.Cell)
+	case ActionHint:
+		return fmt.Sprintf("hint on %v", action.Cell)
+	}
+
+	return fmt.Sprintf("%s %d on %v", action.Kind, action.Value, action.Cell)
+}
+
+// MoveLog is the list of the actions of a player on a game, oldest first,
+// with the sudoku they started from. Every intermediate state of the game can
+// be rebuilt from it, see @State.
+type MoveLog struct {
+	puzzle  Sudoku
+	actions []Action
+}
+
+// Returns an empty log of the actions made on the sudoku.
+func NewMoveLog(puzzle Sudoku) *MoveLog {
+	return &MoveLog{puzzle: puzzle}
+}
+
+// Returns the sudoku the actions start from.
+func (moves *MoveLog) Puzzle() Sudoku {
+	return moves.puzzle
+}
+
+// Returns the actions of the log, oldest first.
+func (moves *MoveLog) Actions() []Action {
+	return append([]Action(nil), moves.actions...)
+}
+
+// Returns the number of actions of the log.
+func (moves *MoveLog) Len() int {
+	return len(moves.actions)
+}
+
+// Applies the action to the sudoku. Hints don't change it.
+func applyAction(sudoku *Sudoku, action Action) {
+	x, y := action.Cell.Row, action.Cell.Column
+
+	switch action.Kind {
+	case ActionPlace:
+		sudoku.values[x][y] = action.Value
+	case ActionClear:
+		sudoku.values[x][y] = 0
+	case ActionCandidate:
+		sudoku.candidates[x][y] ^= 1 << action.Value
+	}
+}
+
+// Returns the sudoku right after the first n actions, the puzzle if n is 0.
+func (moves *MoveLog) State(n int) (Sudoku, error) {
+	if n < 0 || n > len(moves.actions) {
+		return Sudoku{}, errors.New("Sudoku: No such action.")
+	}
+
+	sudoku := moves.puzzle
+
+	for _, action := range moves.actions[:n] {
+		applyAction(&sudoku, action)
+	}
+
+	return sudoku, nil
+}
+
+// Returns the sudoku at the given time of the timer of the game, after every
+// action made until then.
+func (moves *MoveLog) StateAt(t time.Duration) Sudoku {
+	n := 0
+
+	for n < len(moves.actions) && moves.actions[n].Time <= t {
+		n++
+	}
+
+	sudoku, _ := moves.State(n)
+
+	return sudoku
+}
+
+// A move log is saved as the following object.
+//
+//	{
+//	  "version": 1,
+//	  "puzzle": {"version": 1, "givens": "...", ...},
+//	  "actions": "0:p024 1500:t017 2210:h453 ..."
+//	}
+//
+// "puzzle" is the JSON form of the sudoku the actions start from, see
+// @jsonSudoku, and "actions" the actions in the form of @Action.String,
+// separated by spaces.
+type jsonMoveLog struct {
+	Version int     `json:"version"`
+	Puzzle  *Sudoku `json:"puzzle"`
+	Actions string  `json:"actions"`
+}
+
+// MarshalJSON encodes the log as described in @jsonMoveLog.
+func (moves *MoveLog) MarshalJSON() ([]byte, error) {
+	actions := make([]string, len(moves.actions))
+
+	for k, action := range moves.actions {
+		actions[k] = action.String()
+	}
+
+	return json.Marshal(jsonMoveLog{moveLogVersion, &moves.puzzle, strings.Join(actions, " ")})
+}
+
+// UnmarshalJSON decodes a log written by @MarshalJSON. The puzzle is required,
+// and the actions must be in order and must not change its initial values.
+func (moves *MoveLog) UnmarshalJSON(data []byte) error {
+	var doc jsonMoveLog
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version == 0 {
+		return errors.New("Sudoku: Not a move log.")
+	}
+
+	if doc.Version > moveLogVersion {
+		return fmt.Errorf("Sudoku: Unsupported move log version %d.", doc.Version)
+	}
+
+	if doc.Puzzle == nil {
+		return errors.New("Sudoku: Move log without a puzzle.")
+	}
+
+	var actions []Action
+
+	for _, field := range strings.Fields(doc.Actions) {
+		action, err := parseAction(field)
+		if err != nil {
+			return err
+		}
+
+		if len(actions) > 0 && action.Time < actions[len(actions)-1].Time {
+			return errors.New("Sudoku: Actions out of order.")
+		}
+
+		if action.Kind != ActionHint && doc.Puzzle.initialValues[action.Cell.Row][action.Cell.Column] != 0 {
+			return errors.New("Sudoku: Action changes an initial value.")
+		}
+
+		actions = append(actions, action)
+	}
+
+	*moves = MoveLog{puzzle: *doc.Puzzle, actions: actions}
+
+	return nil
+}
+
+// Saves the log to the file on path, replacing it atomically.
+func (moves *MoveLog) SaveFile(path string) error {
+	data, err := json.Marshal(moves)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// Starts logging the actions of the player from the current state of the
+// sudoku, replacing the log in progress, and returns the log. Undone and
+// redone moves are logged as the actions which revert and repeat them.
+func (game *Game) StartLog() *MoveLog {
+	game.moves = NewMoveLog(game.sudoku)
+
+	return game.moves
+}
+
+// Returns the log in progress, nil if the game isn't logged.
+func (game *Game) MoveLog() *MoveLog {
+	return game.moves
+}
+
+// Adds the actions which make the change to the log in progress, if any.
+func (game *Game) logChange(change cellChange) {
+	if game.moves == nil {
+		return
+	}
+
+	action := Action{Time: game.Elapsed(), Cell: change.cell}
+
+	if change.beforeValue != change.afterValue {
+		action.Kind, action.Value = ActionPlace, change.afterValue
+
+		if change.afterValue == 0 {
+			action.Kind = ActionClear
+		}
+
+		game.moves.actions = append(game.moves.actions, action)
+	}
+
+	for _, val := range maskDigits(change.beforeCandidates ^ change.afterCandidates) {
+		action.Kind, action.Value = ActionCandidate, val
+		game.moves.actions = append(game.moves.actions, action)
+	}
+}
+
+// Adds the hint taken to the log in progress, if any.
+func (game *Game) logHint(hint Hint) {
+	if game.moves == nil {
+		return
+	}
+
+	action := Action{Time: game.Elapsed(), Kind: ActionHint}
+
+	if hint.Placement != nil {
+		action.Cell, action.Value = hint.Placement.Cell, hint.Placement.Value
+	} else if len(hint.Eliminations) > 0 {
+		action.Cell, action.Value = hint.Eliminations[0].Cell, hint.Eliminations[0].Value
+	}
+
+	game.moves.actions = append(game.moves.actions, action)
+}
+
+// The longest a replay waits between two actions, so the time a player spent
+// thinking doesn't stop it. The time shown keeps the real one.
+const replayMaxWait = 3 * time.Second
+
+// The time the last state of an animation is shown before it starts again.
+const replayHold = 3 * time.Second
+
+// Replayer plays back a move log.
+type Replayer struct {
+	moves *MoveLog
+
+	// How many times faster than the player the actions are replayed.
+	speed float64
+
+	sleep func(d time.Duration)
+}
+
+// Returns a replayer of the log at the given speed, like 2 to replay it twice
+// as fast as it was played. A speed of 0 or less replays it as it was played.
+func NewReplayer(moves *MoveLog, speed float64) *Replayer {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &Replayer{moves: moves, speed: speed, sleep: time.Sleep}
+}
+
+// Returns how long every state is shown: the first one is the puzzle, and the
+// state after the action k is k+1. The time between two actions is divided by
+// the speed and shortened to replayMaxWait; the last state is shown for
+// replayHold. Actions made at once share a state, shown for 0.
+func (replayer *Replayer) durations() []time.Duration {
+	actions := replayer.moves.actions
+	durations := make([]time.Duration, len(actions)+1)
+
+	for k := range actions {
+		previous := time.Duration(0)
+		if k > 0 {
+			previous = actions[k-1].Time
+		}
+
+		wait := time.Duration(float64(actions[k].Time-previous) / replayer.speed)
+		if wait > replayMaxWait {
+			wait = replayMaxWait
+		}
+
+		durations[k] = wait
+	}
+
+	durations[len(actions)] = replayHold
+
+	return durations
+}
+
+// Calls fn with every state of the replay shown for some time, see
+// @durations, and the number of actions which led to it.
+func (replayer *Replayer) frames(fn func(sudoku *Sudoku, n int, duration time.Duration)) {
+	sudoku := replayer.moves.puzzle
+
+	for n, duration := range replayer.durations() {
+		if n > 0 {
+			applyAction(&sudoku, replayer.moves.actions[n-1])
+		}
+
+		if duration > 0 {
+			fn(&sudoku, n, duration)
+		}
+	}
+}
+
+// Play draws the replay on a terminal, waiting between the actions as the
+// player did at the speed of the replayer. The cell of every action is under
+// the cursor, and the status line shows the time of the game and the action.
+func (replayer *Replayer) Play(out io.Writer) error {
+	total := len(replayer.moves.actions)
+	var err error
+
+	replayer.frames(func(sudoku *Sudoku, n int, duration time.Duration) {
+		if err != nil {
+			return
+		}
+
+		status := "Time 00:00"
+		renderer := Renderer{Mode: CandidateMode, Color: true}
+
+		if n > 0 {
+			action := replayer.moves.actions[n-1]
+			status = fmt.Sprintf("Time %s  Action %d/%d  %s", formatDuration(action.Time), n, total, action.Text())
+			renderer.Cursor = &action.Cell
+		}
+
+		if _, err = io.WriteString(out, ansiClear+status+"\n"+renderer.Render(sudoku)); err != nil {
+			return
+		}
+
+		if n < total {
+			replayer.sleep(duration)
+		}
+	})
+
+	return err
+}
+
+// ExportSVG writes the replay as an animated SVG image which loops forever:
+// every state is drawn as in @RenderSVG, with the cell of its action
+// highlighted, and shown for as long as @Play would.
+func (replayer *Replayer) ExportSVG(w io.Writer, opts SVGOptions) error {
+	var total time.Duration
+
+	for _, duration := range replayer.durations() {
+		total += duration
+	}
+
+	var b strings.Builder
+	var start time.Duration
+
+	svgStart(&b, opts)
+
+	replayer.frames(func(sudoku *Sudoku, n int, duration time.Duration) {
+		frame := opts
+		frame.Highlights = map[Cell]string{}
+
+		for cell, color := range opts.Highlights {
+			frame.Highlights[cell] = color
+		}
+
+		if n > 0 {
+			frame.Highlights[replayer.moves.actions[n-1].Cell] = "#ffe680"
+		}
+
+		end := start + duration
+		from := float64(start) / float64(total)
+		to := float64(end) / float64(total)
+
+		// The first state stays visible where animations are not supported.
+		switch {
+		case start == 0 && end == total:
+			b.WriteString("<g>\n")
+		case start == 0:
+			fmt.Fprintf(&b, `<g><animate attributeName="visibility" values="visible;hidden" keyTimes="0;%.4f" dur="%.3fs" calcMode="discrete" repeatCount="indefinite"/>`+"\n",
+				to, total.Seconds())
+		case end == total:
+			fmt.Fprintf(&b, `<g visibility="hidden"><animate attributeName="visibility" values="hidden;visible" keyTimes="0;%.4f" dur="%.3fs" calcMode="discrete" repeatCount="indefinite"/>`+"\n",
+				from, total.Seconds())
+		default:
+			fmt.Fprintf(&b, `<g visibility="hidden"><animate attributeName="visibility" values="hidden;visible;hidden" keyTimes="0;%.4f;%.4f" dur="%.3fs" calcMode="discrete" repeatCount="indefinite"/>`+"\n",
+				from, to, total.Seconds())
+		}
+
+		sudoku.writeSVG(&b, frame)
+		b.WriteString("</g>\n")
+		start = end
+	})
+
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
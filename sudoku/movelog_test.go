@@ -0,0 +1,153 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Plays a few moves on a logged game: a value, a candidate, a value which
+// is undone and redone, a hint and a clear.
+func loggedGame() (*Game, *fakeClock) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	clock := &fakeClock{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	game := NewGame(sudoku)
+	game.SetClock(clock)
+	game.StartLog()
+
+	clock.Advance(time.Second)
+	game.SetValue(0, 2, 4)
+	clock.Advance(500 * time.Millisecond)
+	game.ToggleCandidate(0, 3, 6)
+	clock.Advance(time.Minute)
+	game.SetValue(0, 5, 8)
+	game.Undo()
+	game.Redo()
+	clock.Advance(time.Second)
+	game.NextHint()
+	game.ClearValue(0, 5)
+
+	return game, clock
+}
+
+func TestMoveLog(t *testing.T) {
+	game, _ := loggedGame()
+	moves := game.MoveLog()
+
+	want := []string{"1000:p024", "1500:t036", "61500:p058", "61500:c050", "61500:p058", "62500:h", "62500:c050"}
+	actions := moves.Actions()
+
+	if len(actions) != len(want) {
+		t.Fatalf("Sudoku: Log should have %d actions but has %v", len(want), actions)
+	}
+
+	for k, action := range actions {
+		// The hint depends on the technique found first, only its kind is
+		// checked.
+		if !strings.HasPrefix(action.String(), want[k]) {
+			t.Errorf("Sudoku: Action %d should be %s but is %s", k, want[k], action)
+		}
+	}
+
+	sudoku, err := moves.State(moves.Len())
+	current := game.GetSudoku()
+
+	if err != nil || sudoku.values != current.values || sudoku.candidates != current.candidates {
+		t.Errorf("Sudoku: Last state of the log should be the game")
+	}
+
+	if sudoku, _ := moves.State(2); sudoku.values[0][2] != 4 || sudoku.candidates[0][3] != 1<<6 || sudoku.values[0][5] != 0 {
+		t.Errorf("Sudoku: State after 2 actions should have a 4 and a candidate 6")
+	}
+
+	if sudoku := moves.StateAt(time.Minute); sudoku.values[0][2] != 4 || sudoku.values[0][5] != 0 {
+		t.Errorf("Sudoku: State at 1m0s should only have a 4")
+	}
+
+	if _, err := moves.State(moves.Len() + 1); err == nil {
+		t.Errorf("Sudoku: State after too many actions should fail")
+	}
+}
+
+func TestMoveLogJSON(t *testing.T) {
+	game, _ := loggedGame()
+
+	data, err := json.Marshal(game.MoveLog())
+	if err != nil {
+		t.Fatalf("Sudoku: Encoding the log failed: %v", err)
+	}
+
+	var decoded MoveLog
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Sudoku: Decoding the log failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Actions(), game.MoveLog().Actions()) {
+		t.Errorf("Sudoku: Decoded actions should be %v but are %v", game.MoveLog().Actions(), decoded.Actions())
+	}
+
+	// The log is saved with the game.
+	data, _ = json.Marshal(game)
+	var loaded Game
+
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.MoveLog() == nil || loaded.MoveLog().Len() != game.MoveLog().Len() {
+		t.Errorf("Sudoku: Loaded game should keep its log")
+	}
+
+	invalid := []string{
+		`{"version": 1, "puzzle": {"version": 1, "givens": "5"}, "actions": "100:p001"}`,
+		`{"version": 1, "puzzle": {"version": 1}, "actions": "100:p024 50:p034"}`,
+		`{"version": 1, "puzzle": {"version": 1}, "actions": "100:x024"}`,
+		`{"version": 1, "puzzle": {"version": 1}, "actions": "100:p094"}`,
+		`{"version": 1, "puzzle": {"version": 1}, "actions": "100:p020"}`,
+		`{"version": 2, "puzzle": {"version": 1}, "actions": ""}`,
+		`{"puzzle": {"version": 1}}`,
+		`{"version": 1, "actions": "100:p024"}`,
+		`{"version": 1, "sudoku": {"version": 1}, "elapsed": 0}`,
+	}
+
+	for _, doc := range invalid {
+		if err := json.Unmarshal([]byte(doc), &decoded); err == nil {
+			t.Errorf("Sudoku: Log %s should be rejected", doc)
+		}
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	game, _ := loggedGame()
+
+	var waits []time.Duration
+	replayer := NewReplayer(game.MoveLog(), 2)
+	replayer.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	var out strings.Builder
+	if err := replayer.Play(&out); err != nil {
+		t.Fatalf("Sudoku: Replay failed: %v", err)
+	}
+
+	// Actions made at once are shown together, and long waits are cut.
+	want := []time.Duration{500 * time.Millisecond, 250 * time.Millisecond, replayMaxWait, 500 * time.Millisecond}
+	if !reflect.DeepEqual(waits, want) {
+		t.Errorf("Sudoku: Replay should wait %v but waits %v", want, waits)
+	}
+
+	if frames := strings.Count(out.String(), ansiClear); frames != 5 {
+		t.Errorf("Sudoku: Replay should draw 5 frames but draws %d", frames)
+	}
+
+	if !strings.Contains(out.String(), "Action 7/7  clear r1c6") {
+		t.Errorf("Sudoku: Last frame should show the last action")
+	}
+
+	var svg strings.Builder
+	if err := replayer.ExportSVG(&svg, SVGOptions{}); err != nil {
+		t.Fatalf("Sudoku: Exporting the replay failed: %v", err)
+	}
+
+	if frames := strings.Count(svg.String(), "<animate "); frames != 5 || !strings.HasSuffix(svg.String(), "</svg>\n") {
+		t.Errorf("Sudoku: Animation should have 5 frames but has %d", frames)
+	}
+}
@@ -0,0 +1,508 @@
+package sudoku
+
+import (
+	"encoding/json" // Messages.
+	"errors"        // Error handling.
+	"net/http"      // WebSocket endpoint.
+	"sync"          // Shared state.
+	"time"          // Removing abandoned sessions.
+)
+
+// The modes of a @Session.
+const (
+	// Every player fills their own copy of the puzzle, and the first one to
+	// complete it wins.
+	RaceMode = "race"
+
+	// All the players fill the same grid together.
+	CoopMode = "coop"
+)
+
+// The types of @SessionEvent.
+const (
+	// Sent to a player when joining, with the board they play on.
+	EventState = "state"
+
+	// A player joined or left the session.
+	EventJoined = "joined"
+	EventLeft   = "left"
+
+	// A value was written or cleared on the shared grid of a co-op session.
+	EventMove = "move"
+
+	// The number of filled cells of a player of a race changed.
+	EventProgress = "progress"
+
+	// A move of the player was not applied, see Message.
+	EventRejected = "rejected"
+
+	// The puzzle was completed. In a race Player is the winner, in co-op the
+	// player who wrote the last value.
+	EventWinner = "winner"
+)
+
+// The number of events a player may have pending before being dropped from
+// the session for reading too slowly.
+const sessionBuffer = 256
+
+// The time a session of a @Hub is kept without players before it's removed.
+const hubIdleTimeout = time.Hour
+
+// Move writes Value on a cell of the board of a session, or clears it if
+// Value is 0. In co-op, Version is the version of the cell the player last
+// saw, see @SessionEvent; the move is rejected if another player changed the
+// cell since then.
+type Move struct {
+	Row     int `json:"row"`
+	Column  int `json:"column"`
+	Value   int `json:"value"`
+	Version int `json:"version"`
+}
+
+// SessionEvent is a message sent to the players of a session.
+type SessionEvent struct {
+	Type   string `json:"type"`
+	Player string `json:"player,omitempty"`
+
+	// The cell and value of EventMove and EventRejected, and the version of
+	// the cell after the move, or the current one for a rejected move.
+	Row     int `json:"row"`
+	Column  int `json:"column"`
+	Value   int `json:"value"`
+	Version int `json:"version"`
+
+	// The filled cells of the player, for EventProgress.
+	Filled int `json:"filled,omitempty"`
+
+	// The board of EventState and the reason of EventRejected.
+	Board    *Sudoku    `json:"board,omitempty"`
+	Versions *[9][9]int `json:"versions,omitempty"`
+	Message  string     `json:"message,omitempty"`
+}
+
+// A player of a session: their board in a race, and their pending events.
+type sessionPlayer struct {
+	board  *Sudoku
+	events chan SessionEvent
+}
+
+// Session is a multiplayer game which owns the authoritative boards. Moves are
+// applied one at a time under a lock and the result is broadcast to all the
+// players.
+type Session struct {
+	mode   string
+	puzzle Sudoku
+
+	mu      sync.Mutex
+	players map[string]*sessionPlayer
+
+	// The shared board of a co-op session, and the number of times each of
+	// its cells changed.
+	board    Sudoku
+	versions [9][9]int
+
+	winner string
+
+	// The clock of the hub, and the time the last player left or the session
+	// was created, while nobody plays it.
+	clock      Clock
+	emptySince time.Time
+}
+
+// Returns a new session of the puzzle in RaceMode or CoopMode.
+func NewSession(mode string, puzzle Sudoku) (*Session, error) {
+	if mode != RaceMode && mode != CoopMode {
+		return nil, errors.New("Sudoku: Unknown session mode.")
+	}
+
+	puzzle.Reset()
+
+	session := &Session{mode: mode, puzzle: puzzle, board: puzzle, players: map[string]*sessionPlayer{}, clock: systemClock{}}
+	session.emptySince = session.clock.Now()
+
+	return session, nil
+}
+
+// Removes the player, closing their events. Must be called with the lock
+// held.
+func (session *Session) remove(name string, player *sessionPlayer) {
+	close(player.events)
+	delete(session.players, name)
+
+	if len(session.players) == 0 {
+		session.emptySince = session.clock.Now()
+	}
+}
+
+// Sends the event to every player. Players who can't keep up are dropped.
+// Must be called with the lock held.
+func (session *Session) broadcast(event SessionEvent) {
+	for name, player := range session.players {
+		select {
+		case player.events <- event:
+		default:
+			session.remove(name, player)
+		}
+	}
+}
+
+// Adds the player to the session and returns the events sent to them, which
+// start with EventState. The channel is closed when the player leaves. An
+// error is returned if the name is empty or taken.
+func (session *Session) Join(name string) (<-chan SessionEvent, error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if name == "" {
+		return nil, errors.New("Sudoku: Player without a name.")
+	}
+
+	if _, ok := session.players[name]; ok {
+		return nil, errors.New("Sudoku: The name is taken.")
+	}
+
+	session.broadcast(SessionEvent{Type: EventJoined, Player: name})
+
+	player := &sessionPlayer{events: make(chan SessionEvent, sessionBuffer)}
+	state := SessionEvent{Type: EventState, Player: name}
+
+	// The event gets copies, so later moves don't change it.
+	var board Sudoku
+
+	if session.mode == RaceMode {
+		board = session.puzzle
+		own := session.puzzle
+		player.board = &own
+	} else {
+		board = session.board
+		versions := session.versions
+		state.Versions = &versions
+	}
+
+	state.Board = &board
+	player.events <- state
+
+	if session.winner != "" {
+		player.events <- SessionEvent{Type: EventWinner, Player: session.winner}
+	}
+
+	session.players[name] = player
+
+	return player.events, nil
+}
+
+// Removes the player from the session.
+func (session *Session) Leave(name string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	player, ok := session.players[name]
+	if !ok {
+		return
+	}
+
+	session.remove(name, player)
+	session.broadcast(SessionEvent{Type: EventLeft, Player: name})
+}
+
+// Applies the move of the player, see @Move, and broadcasts its result. An
+// error is returned if the move is rejected, in which case only the player
+// gets an EventRejected.
+func (session *Session) Move(name string, move Move) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	player, ok := session.players[name]
+	if !ok {
+		return errors.New("Sudoku: Unknown player.")
+	}
+
+	err := session.apply(player, name, move)
+
+	if err != nil {
+		event := SessionEvent{Type: EventRejected, Player: name, Row: move.Row, Column: move.Column, Message: err.Error()}
+
+		if session.mode == CoopMode && move.Row >= 0 && move.Row <= 8 && move.Column >= 0 && move.Column <= 8 {
+			event.Value = session.board.values[move.Row][move.Column]
+			event.Version = session.versions[move.Row][move.Column]
+		}
+
+		select {
+		case player.events <- event:
+		default:
+		}
+	}
+
+	return err
+}
+
+// Applies the move to the board of the player. Must be called with the lock
+// held.
+func (session *Session) apply(player *sessionPlayer, name string, move Move) error {
+	if session.winner != "" {
+		return errors.New("Sudoku: The game is over.")
+	}
+
+	board := player.board
+	if session.mode == CoopMode {
+		board = &session.board
+
+		// Moves made on an old version of the cell lose against the move
+		// which changed it first.
+		if move.Row >= 0 && move.Row <= 8 && move.Column >= 0 && move.Column <= 8 &&
+			session.versions[move.Row][move.Column] != move.Version {
+			return errors.New("Sudoku: The cell was changed by another player.")
+		}
+	}
+
+	var err error
+
+	if move.Value == 0 {
+		err = board.ClearValue(move.Row, move.Column)
+	} else {
+		err = board.SetValue(move.Row, move.Column, move.Value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if session.mode == CoopMode {
+		session.versions[move.Row][move.Column]++
+		session.broadcast(SessionEvent{
+			Type:    EventMove,
+			Player:  name,
+			Row:     move.Row,
+			Column:  move.Column,
+			Value:   move.Value,
+			Version: session.versions[move.Row][move.Column],
+		})
+	} else {
+		session.broadcast(SessionEvent{Type: EventProgress, Player: name, Filled: board.FilledCount()})
+	}
+
+	if board.IsComplete() {
+		session.winner = name
+		session.broadcast(SessionEvent{Type: EventWinner, Player: name})
+	}
+
+	return nil
+}
+
+// Returns the winner of the session, empty while the puzzle is not complete.
+func (session *Session) Winner() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	return session.winner
+}
+
+// Returns whether nobody plays the session and either its puzzle was
+// completed or nobody joined it for the given time.
+func (session *Session) abandoned(timeout time.Duration) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if len(session.players) > 0 {
+		return false
+	}
+
+	return session.winner != "" || session.clock.Now().Sub(session.emptySince) >= timeout
+}
+
+// ServeHTTP plays the session over a WebSocket: the player given by the
+// "player" query parameter sends @Move messages as JSON text frames and gets
+// every @SessionEvent as one.
+func (session *Session) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("player")
+
+	if name == "" {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Player without a name."))
+		return
+	}
+
+	// A request which can't be upgraded must not show up as a player who
+	// joins and leaves right away.
+	key, err := wsCheck(w, r)
+	if err != nil {
+		return
+	}
+
+	events, err := session.Join(name)
+	if err != nil {
+		writeAPIError(w, http.StatusConflict, errConflict, err)
+		return
+	}
+
+	ws, err := wsHijack(w, key)
+	if err != nil {
+		session.Leave(name)
+		return
+	}
+
+	defer ws.Close()
+
+	go func() {
+		for event := range events {
+			data, _ := json.Marshal(event)
+
+			if ws.WriteMessage(data) != nil {
+				break
+			}
+		}
+
+		// Dropped or left: make the reading loop end as well.
+		ws.conn.Close()
+	}()
+
+	defer session.Leave(name)
+
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var move Move
+
+		if err := json.Unmarshal(data, &move); err != nil {
+			continue
+		}
+
+		session.Move(name, move)
+	}
+}
+
+// The largest puzzle accepted by @Hub.ServeHTTP to create a session.
+const hubMaxBody = 64 << 10
+
+// The response of POST /play, see @Hub.ServeHTTP.
+type hubSession struct {
+	Session string `json:"session"`
+	Mode    string `json:"mode"`
+}
+
+// Hub holds the sessions being played, each one reachable on /play?session=ID.
+// A session is removed once its puzzle is completed and its last player
+// leaves, or after an hour without players.
+type Hub struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	clock    Clock
+}
+
+// Returns a hub without sessions.
+func NewHub() *Hub {
+	return &Hub{sessions: map[string]*Session{}, clock: systemClock{}}
+}
+
+// Replaces the clock which tells how long sessions have been without
+// players, like a fake one in tests. Sessions created before keep theirs.
+func (hub *Hub) SetClock(clock Clock) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.clock = clock
+}
+
+// Creates a session with the given id, see @NewSession.
+func (hub *Hub) CreateSession(id, mode string, puzzle Sudoku) (*Session, error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if _, ok := hub.sessions[id]; ok || id == "" {
+		return nil, errors.New("Sudoku: Session id is empty or taken.")
+	}
+
+	session, err := NewSession(mode, puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	session.clock = hub.clock
+	session.emptySince = hub.clock.Now()
+	hub.sessions[id] = session
+
+	return session, nil
+}
+
+// Removes the session, the players already in it keep playing.
+func (hub *Hub) RemoveSession(id string) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	delete(hub.sessions, id)
+}
+
+// Removes the abandoned sessions, see @Hub.
+func (hub *Hub) prune() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for id, session := range hub.sessions {
+		if session.abandoned(hubIdleTimeout) {
+			delete(hub.sessions, id)
+		}
+	}
+}
+
+// ServeHTTP joins the player to the session given by the "session" query
+// parameter, see @Session.ServeHTTP. A POST creates the session instead, in
+// the "mode" query parameter, CoopMode if empty, with the sudoku of the body.
+func (hub *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hub.prune()
+
+	if r.Method == http.MethodPost {
+		hub.create(w, r)
+		return
+	}
+
+	hub.mu.Lock()
+	session, ok := hub.sessions[r.URL.Query().Get("session")]
+	hub.mu.Unlock()
+
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, errNotFound, errors.New("Sudoku: Unknown session."))
+		return
+	}
+
+	session.ServeHTTP(w, r)
+
+	// The player left, maybe the last one of a finished game.
+	hub.prune()
+}
+
+// Creates the session of a POST request, see @ServeHTTP.
+func (hub *Hub) create(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id := query.Get("session")
+	mode := query.Get("mode")
+
+	if mode == "" {
+		mode = CoopMode
+	}
+
+	if id == "" {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Session without an id."))
+		return
+	}
+
+	if mode != RaceMode && mode != CoopMode {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Unknown session mode."))
+		return
+	}
+
+	var puzzle Sudoku
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, hubMaxBody)).Decode(&puzzle); err != nil {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, err)
+		return
+	}
+
+	if _, err := hub.CreateSession(id, mode, puzzle); err != nil {
+		writeAPIError(w, http.StatusConflict, errConflict, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, hubSession{id, mode})
+}
@@ -0,0 +1,284 @@
+package sudoku
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns the next event of the channel, failing if none comes.
+func nextEvent(t *testing.T, events <-chan SessionEvent) SessionEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Sudoku: No event received.")
+	}
+
+	return SessionEvent{}
+}
+
+// Returns a puzzle with only the cell (0, 2) left, which takes a 4.
+func almostSolved() Sudoku {
+	sudoku, _ := Parse("53.678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	return sudoku
+}
+
+func TestCoopSession(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	session, _ := NewSession(CoopMode, sudoku)
+
+	alice, _ := session.Join("alice")
+	if event := nextEvent(t, alice); event.Type != EventState || event.Board == nil || event.Versions == nil {
+		t.Errorf("Sudoku: First event is %+v", event)
+	}
+
+	bob, _ := session.Join("bob")
+	nextEvent(t, bob)
+
+	if event := nextEvent(t, alice); event.Type != EventJoined || event.Player != "bob" {
+		t.Errorf("Sudoku: Expected bob to join but got %+v", event)
+	}
+
+	if _, err := session.Join("bob"); err == nil {
+		t.Errorf("Sudoku: Two players with the same name.")
+	}
+
+	// Both write on (0, 2) having seen its version 0, the first one wins.
+	if err := session.Move("alice", Move{0, 2, 4, 0}); err != nil {
+		t.Fatalf("Sudoku: Move rejected: %v", err)
+	}
+
+	if err := session.Move("bob", Move{0, 2, 1, 0}); err == nil {
+		t.Errorf("Sudoku: Stale move was applied.")
+	}
+
+	for _, events := range []<-chan SessionEvent{alice, bob} {
+		if event := nextEvent(t, events); event.Type != EventMove || event.Value != 4 || event.Version != 1 {
+			t.Errorf("Sudoku: Expected the move of alice but got %+v", event)
+		}
+	}
+
+	if event := nextEvent(t, bob); event.Type != EventRejected || event.Value != 4 || event.Version != 1 {
+		t.Errorf("Sudoku: Expected the rejection but got %+v", event)
+	}
+
+	// With the current version the move goes through.
+	if err := session.Move("bob", Move{0, 2, 1, 1}); err != nil {
+		t.Errorf("Sudoku: Move on the current version rejected: %v", err)
+	}
+
+	session.Leave("bob")
+
+	// The channel of bob is closed once its events are read.
+	for range bob {
+	}
+
+	if event := nextEvent(t, alice); event.Type != EventMove || event.Version != 2 {
+		t.Errorf("Sudoku: Expected the move of bob but got %+v", event)
+	}
+
+	if event := nextEvent(t, alice); event.Type != EventLeft || event.Player != "bob" {
+		t.Errorf("Sudoku: Expected bob to leave but got %+v", event)
+	}
+}
+
+func TestRaceSession(t *testing.T) {
+	session, _ := NewSession(RaceMode, almostSolved())
+
+	alice, _ := session.Join("alice")
+	bob, _ := session.Join("bob")
+	nextEvent(t, alice)
+	nextEvent(t, alice)
+	nextEvent(t, bob)
+
+	// Each player has their own board.
+	session.Move("bob", Move{Row: 0, Column: 2, Value: 1})
+
+	if event := nextEvent(t, alice); event.Type != EventProgress || event.Player != "bob" || event.Filled != 81 {
+		t.Errorf("Sudoku: Expected the progress of bob but got %+v", event)
+	}
+
+	session.Move("alice", Move{Row: 0, Column: 2, Value: 4})
+	nextEvent(t, alice)
+
+	if event := nextEvent(t, alice); event.Type != EventWinner || event.Player != "alice" || session.Winner() != "alice" {
+		t.Errorf("Sudoku: Expected alice to win but got %+v", event)
+	}
+
+	if err := session.Move("bob", Move{Row: 0, Column: 2, Value: 4}); err == nil {
+		t.Errorf("Sudoku: Move accepted after the end of the race.")
+	}
+}
+
+func TestSessionWebSocket(t *testing.T) {
+	hub := NewHub()
+	hub.CreateSession("table", CoopMode, almostSolved())
+
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	base := "ws" + strings.TrimPrefix(server.URL, "http") + "/play?session=table&player="
+
+	// Reads the next event of the connection.
+	read := func(ws *wsConn) SessionEvent {
+		t.Helper()
+
+		ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		data, err := ws.ReadMessage()
+		if err != nil {
+			t.Fatalf("Sudoku: Can't read event: %v", err)
+		}
+
+		var event SessionEvent
+		json.Unmarshal(data, &event)
+
+		return event
+	}
+
+	alice, err := wsDial(base + "alice")
+	if err != nil {
+		t.Fatalf("Sudoku: Can't connect: %v", err)
+	}
+
+	defer alice.Close()
+
+	if event := read(alice); event.Type != EventState || event.Board.FilledCount() != 80 {
+		t.Errorf("Sudoku: First event is %+v", event)
+	}
+
+	bob, err := wsDial(base + "bob")
+	if err != nil {
+		t.Fatalf("Sudoku: Can't connect: %v", err)
+	}
+
+	defer bob.Close()
+
+	read(bob)
+	read(alice)
+
+	data, _ := json.Marshal(Move{0, 2, 4, 0})
+	bob.WriteMessage(data)
+
+	for _, ws := range []*wsConn{alice, bob} {
+		if event := read(ws); event.Type != EventMove || event.Player != "bob" {
+			t.Errorf("Sudoku: Expected the move of bob but got %+v", event)
+		}
+
+		if event := read(ws); event.Type != EventWinner || event.Player != "bob" {
+			t.Errorf("Sudoku: Expected bob to complete the puzzle but got %+v", event)
+		}
+	}
+
+	if _, err := wsDial(base + "alice"); err == nil {
+		t.Errorf("Sudoku: Two connections with the same name.")
+	}
+
+	var body apiError
+	if code := request(t, hub, "GET", "/play?session=table", "", &body); code != http.StatusBadRequest {
+		t.Errorf("Sudoku: Player without a name got %d and %+v", code, body)
+	}
+
+	if _, err := wsDial("ws" + strings.TrimPrefix(server.URL, "http") + "/play?session=none&player=carol"); err == nil {
+		t.Errorf("Sudoku: Joined an unknown session.")
+	}
+}
+
+func TestSessionHandshake(t *testing.T) {
+	hub := NewHub()
+	session, _ := hub.CreateSession("table", CoopMode, almostSolved())
+
+	alice, _ := session.Join("alice")
+	nextEvent(t, alice)
+
+	// A request which is not a handshake doesn't join bob.
+	recorder := httptest.NewRecorder()
+	hub.ServeHTTP(recorder, httptest.NewRequest("GET", "/play?session=table&player=bob", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Sudoku: Request without a handshake got %d", recorder.Code)
+	}
+
+	select {
+	case event := <-alice:
+		t.Errorf("Sudoku: Request without a handshake sent %+v", event)
+	default:
+	}
+}
+
+func TestHubRemovesSessions(t *testing.T) {
+	clock := &fakeClock{time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	hub := NewHub()
+	hub.SetClock(clock)
+
+	finished, _ := hub.CreateSession("finished", CoopMode, almostSolved())
+	hub.CreateSession("idle", CoopMode, almostSolved())
+	playing, _ := hub.CreateSession("playing", CoopMode, almostSolved())
+
+	finished.Join("alice")
+	finished.Move("alice", Move{Row: 0, Column: 2, Value: 4})
+	playing.Join("bob")
+
+	// A finished game stays while someone is in it.
+	hub.prune()
+
+	if len(hub.sessions) != 3 {
+		t.Errorf("Sudoku: Sessions left after a game ended: %v", hub.sessions)
+	}
+
+	finished.Leave("alice")
+	hub.prune()
+
+	if _, ok := hub.sessions["finished"]; ok || len(hub.sessions) != 2 {
+		t.Errorf("Sudoku: Sessions left after the winner left: %v", hub.sessions)
+	}
+
+	clock.Advance(hubIdleTimeout)
+	hub.prune()
+
+	if _, ok := hub.sessions["playing"]; !ok || len(hub.sessions) != 1 {
+		t.Errorf("Sudoku: Sessions left after an hour: %v", hub.sessions)
+	}
+
+	var body apiError
+	if code := request(t, hub, "GET", "/play?session=idle&player=carol", "", &body); code != http.StatusNotFound {
+		t.Errorf("Sudoku: Joined an idle session with %d and %+v", code, body)
+	}
+}
+
+func TestWebSocketChecks(t *testing.T) {
+	request := httptest.NewRequest("GET", "/play", nil)
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "websocket")
+	request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	request.Header.Set("Sec-WebSocket-Version", "8")
+
+	recorder := httptest.NewRecorder()
+
+	if _, err := wsCheck(recorder, request); err == nil || recorder.Code != http.StatusUpgradeRequired ||
+		recorder.Header().Get("Sec-WebSocket-Version") != "13" {
+		t.Errorf("Sudoku: Upgraded a handshake of version 8 with %d", recorder.Code)
+	}
+
+	// Frames of clients must be masked.
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	unmasked := &wsConn{conn: a, reader: bufio.NewReader(a)}
+	server := &wsConn{conn: b, reader: bufio.NewReader(b)}
+
+	go unmasked.WriteMessage([]byte("{}"))
+
+	if _, err := server.ReadMessage(); err == nil {
+		t.Errorf("Sudoku: Server read an unmasked frame.")
+	}
+}
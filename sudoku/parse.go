@@ -0,0 +1,78 @@
+package sudoku
+
+import (
+	"fmt"     // Error formatting.
+	"unicode" // Whitespace detection.
+)
+
+// ParseError is returned when a puzzle can't be read, it indicates the line
+// and column (both starting at 1) where the problem was found.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("Sudoku: %s (line %d, column %d).", err.Msg, err.Line, err.Column)
+}
+
+// Returns true if the rune is only used to draw the grid and can be ignored
+// while reading a puzzle. Besides the usual ASCII separators this accepts the
+// box drawing characters, so the output of @ToString can be parsed back.
+func isSeparator(r rune) bool {
+	switch r {
+	case '|', '-', '+':
+		return true
+	}
+
+	return r >= '─' && r <= '╿'
+}
+
+// Parse reads a puzzle in the standard 81 character format, one character per
+// cell from left to right and from top to bottom. The digits 1 to 9 are the
+// givens of the puzzle, while '0' and '.' are empty cells. Whitespace and box
+// separators are ignored, so a grid spread over several lines is also
+// accepted. The givens are loaded with @SetInitialValue.
+func Parse(s string) (Sudoku, error) {
+	var sudoku Sudoku
+
+	line, column := 1, 0
+	k := 0
+
+	for _, r := range s {
+		column++
+
+		if r == '\n' {
+			line++
+			column = 0
+			continue
+		}
+
+		if unicode.IsSpace(r) || isSeparator(r) {
+			continue
+		}
+
+		if r != '.' && (r < '0' || r > '9') {
+			return Sudoku{}, &ParseError{line, column, fmt.Sprintf("Unexpected character %q", r)}
+		}
+
+		if k >= 81 {
+			return Sudoku{}, &ParseError{line, column, "Too many cells"}
+		}
+
+		if r >= '1' && r <= '9' {
+			if err := sudoku.SetInitialValue(k/9, k%9, int(r-'0')); err != nil {
+				return Sudoku{}, &ParseError{line, column, err.Error()}
+			}
+		}
+
+		k++
+	}
+
+	if k < 81 {
+		return Sudoku{}, &ParseError{line, column + 1, fmt.Sprintf("Expected 81 cells but found %d", k)}
+	}
+
+	return sudoku, nil
+}
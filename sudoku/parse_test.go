@@ -0,0 +1,85 @@
+package sudoku
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	line := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+
+	sudoku, err := Parse(line)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't parse valid puzzle: %v", err)
+	}
+
+	// Every digit must be loaded as an initial value, every zero as an empty
+	// cell.
+	for k, r := range line {
+		val, _ := sudoku.GetValue(k/9, k%9)
+
+		if val != int(r-'0') {
+			t.Errorf("Sudoku: Value on (%d, %d) should be %d but is %d", k/9, k%9, r-'0', val)
+		}
+
+		if val != 0 && sudoku.SetValue(k/9, k%9, 1) == nil {
+			t.Errorf("Sudoku: Parsed value on (%d, %d) is not an initial value.", k/9, k%9)
+		}
+	}
+
+	// Dots, whitespace and separators must be accepted as well.
+	dotted := strings.ReplaceAll(line, "0", ".")
+	grid := ""
+	for i := 0; i < 9; i++ {
+		grid += dotted[i*9:i*9+3] + "|" + dotted[i*9+3:i*9+6] + "|" + dotted[i*9+6:i*9+9] + "\n"
+		if i == 2 || i == 5 {
+			grid += "---+---+---\n"
+		}
+	}
+
+	for _, s := range []string{dotted, grid} {
+		other, err := Parse(s)
+		if err != nil {
+			t.Errorf("Sudoku: Can't parse valid puzzle %q: %v", s, err)
+		}
+
+		if other.values != sudoku.values {
+			t.Errorf("Sudoku: Parsed %q as\n%v", s, other.ToString())
+		}
+	}
+
+	// The output of ToString must be readable.
+	if other, err := Parse(sudoku.ToString()); err != nil || other.values != sudoku.values {
+		t.Errorf("Sudoku: Can't parse the output of ToString: %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var perr *ParseError
+
+	tests := []struct {
+		input  string
+		line   int
+		column int
+	}{
+		{"53007000x", 1, 9},
+		{strings.Repeat("1", 80), 1, 81},
+		{strings.Repeat("0", 82), 1, 82},
+		{strings.Repeat("0", 9) + "\n" + strings.Repeat("0", 3) + "a", 2, 4},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.input)
+
+		if !errors.As(err, &perr) {
+			t.Errorf("Sudoku: Parsing %q should fail with a ParseError, got %v", test.input, err)
+			continue
+		}
+
+		if perr.Line != test.line || perr.Column != test.column {
+			t.Errorf("Sudoku: Error for %q should be at (%d, %d) but is at (%d, %d)",
+				test.input, test.line, test.column, perr.Line, perr.Column)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package sudoku
+
+import (
+	"bytes"         // Buffers for the PDF objects.
+	"compress/zlib" // Compression of the page contents.
+	"errors"        // Error handling.
+	"fmt"           // String formatting.
+	"io"            // Writers.
+	"strings"       // String manipulation.
+)
+
+// BookletOptions configures @WriteBooklet.
+type BookletOptions struct {
+	// The title printed on the header of every page.
+	Title string
+
+	// The number of puzzles on each page: 1, 2, 4 or 6. 4 if zero.
+	PerPage int
+
+	// Don't add the answer key at the back of the booklet.
+	NoAnswers bool
+}
+
+// The size of an A4 page and its margins in points.
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 40.0
+)
+
+// Builds the content stream of a page with the PDF drawing operators.
+type pdfPage struct {
+	b bytes.Buffer
+}
+
+// Draws a line from (x1, y1) to (x2, y2).
+func (p *pdfPage) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.b, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Writes the text with its baseline starting on (x, y). F1 is Helvetica and F2
+// Helvetica-Bold.
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.b, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDF(s))
+}
+
+// Writes a digit centred on (x, y). All digits of Helvetica are 0.556 em wide.
+func (p *pdfPage) digit(font string, size, x, y float64, val int) {
+	p.text(font, size, x-0.278*size, y-0.35*size, fmt.Sprint(val))
+}
+
+// Draws the sudoku with its top left corner on (x, y) and cells of the given
+// size. Initial values are bold.
+func (p *pdfPage) grid(sudoku *Sudoku, x, y, cell float64) {
+	for k := 0; k <= 9; k++ {
+		width := 0.5
+		if k%3 == 0 {
+			width = 1.5
+		}
+
+		pos := float64(k) * cell
+		p.line(x, y-pos, x+9*cell, y-pos, width)
+		p.line(x+pos, y, x+pos, y-9*cell, width)
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			val := sudoku.values[i][j]
+
+			if val == 0 {
+				continue
+			}
+
+			font := "F1"
+			if sudoku.initialValues[i][j] != 0 {
+				font = "F2"
+			}
+
+			p.digit(font, cell*0.6, x+(float64(j)+0.5)*cell, y-(float64(i)+0.5)*cell, val)
+		}
+	}
+}
+
+// The characters of WinAnsiEncoding on the codes 0x80 to 0x9F, which Latin-1
+// leaves to control characters.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// Returns the string escaped for a PDF string literal in WinAnsiEncoding, the
+// encoding of the standard fonts. Characters it doesn't have are replaced by
+// '?'.
+func escapePDF(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch code, ok := winAnsi[r]; {
+		case ok:
+			fmt.Fprintf(&b, "\\%03o", code)
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || (r >= 127 && r < 0xA0) || r > 255:
+			b.WriteByte('?')
+		case r > 126:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// Returns the label of a puzzle: its number, title and difficulty.
+func puzzleLabel(number int, sudoku *Sudoku) string {
+	label := fmt.Sprintf("Puzzle %d", number)
+
+	if sudoku.metadata.Title != "" {
+		label += ": " + sudoku.metadata.Title
+	}
+
+	if sudoku.metadata.Difficulty != "" {
+		label += " (" + sudoku.metadata.Difficulty + ")"
+	}
+
+	return label
+}
+
+// Lays out count grids with a label above each one in a page with the given
+// number of columns and rows. Calls draw with the position of each grid and
+// the size of its cells.
+func layoutGrids(count, columns, rows int, top float64, draw func(k int, x, y, cell float64)) {
+	label := 20.0
+	boxWidth := (pageWidth - 2*pageMargin) / float64(columns)
+	boxHeight := (top - pageMargin) / float64(rows)
+
+	side := boxWidth - 20
+	if boxHeight-label-20 < side {
+		side = boxHeight - label - 20
+	}
+
+	for k := 0; k < count; k++ {
+		col := k % columns
+		row := k / columns
+
+		x := pageMargin + float64(col)*boxWidth + (boxWidth-side)/2
+		y := top - float64(row)*boxHeight - label
+
+		draw(k, x, y, side/9)
+	}
+}
+
+// WriteBooklet writes a printable PDF booklet of A4 pages with the puzzles,
+// several on each page with their number, title and difficulty taken from
+// their @Metadata. At the back of the booklet an answer key shows the small
+// solved grid of every puzzle. An error is returned if any of the puzzles has
+// no solution.
+func WriteBooklet(w io.Writer, sudokus []Sudoku, opts BookletOptions) error {
+	var pages []*pdfPage
+	var columns, rows int
+
+	switch opts.PerPage {
+	case 0, 4:
+		opts.PerPage, columns, rows = 4, 2, 2
+	case 1:
+		columns, rows = 1, 1
+	case 2:
+		columns, rows = 1, 2
+	case 6:
+		columns, rows = 2, 3
+	default:
+		return errors.New("Sudoku: Puzzles per page must be 1, 2, 4 or 6.")
+	}
+
+	if len(sudokus) == 0 {
+		return errors.New("Sudoku: The booklet has no puzzles.")
+	}
+
+	solutions := make([]Sudoku, len(sudokus))
+
+	if !opts.NoAnswers {
+		for k := range sudokus {
+			solution, err := sudokus[k].Solve()
+			if err != nil {
+				return fmt.Errorf("Sudoku: Puzzle %d has no solution.", k+1)
+			}
+
+			solutions[k] = solution
+		}
+	}
+
+	// Adds a page with the header and returns it with the height left below
+	// the header.
+	newPage := func() (*pdfPage, float64) {
+		page := &pdfPage{}
+		pages = append(pages, page)
+
+		top := pageHeight - pageMargin
+		page.text("F2", 14, pageMargin, top-14, opts.Title)
+		page.text("F1", 10, pageWidth-pageMargin-40, top-14, fmt.Sprintf("Page %d", len(pages)))
+		page.line(pageMargin, top-22, pageWidth-pageMargin, top-22, 0.5)
+
+		return page, top - 40
+	}
+
+	for start := 0; start < len(sudokus); start += opts.PerPage {
+		page, top := newPage()
+		count := len(sudokus) - start
+
+		if count > opts.PerPage {
+			count = opts.PerPage
+		}
+
+		layoutGrids(count, columns, rows, top, func(k int, x, y, cell float64) {
+			sudoku := &sudokus[start+k]
+			puzzle := *sudoku
+			puzzle.values = puzzle.initialValues
+
+			page.text("F2", 11, x, y+8, puzzleLabel(start+k+1, sudoku))
+			page.grid(&puzzle, x, y, cell)
+		})
+	}
+
+	if !opts.NoAnswers {
+		for start := 0; start < len(solutions); start += 12 {
+			page, top := newPage()
+			count := len(solutions) - start
+
+			if count > 12 {
+				count = 12
+			}
+
+			page.text("F2", 12, pageMargin, top, "Answers")
+
+			layoutGrids(count, 3, 4, top-20, func(k int, x, y, cell float64) {
+				page.text("F2", 9, x, y+8, fmt.Sprintf("%d", start+k+1))
+				page.grid(&solutions[start+k], x, y, cell)
+			})
+		}
+	}
+
+	return writePDF(w, pages)
+}
+
+// Writes the PDF document with the given pages, its fonts and the
+// cross-reference table.
+func writePDF(w io.Writer, pages []*pdfPage) error {
+	var out bytes.Buffer
+	var offsets []int
+
+	// Objects 1 and 2 are the catalog and page tree, 3 and 4 the fonts, and
+	// each page takes two objects: the page and its content.
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for k := range pages {
+		kids[k] = fmt.Sprintf("%d 0 R", 5+2*k)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for k, page := range pages {
+		var content bytes.Buffer
+
+		zw := zlib.NewWriter(&content)
+		zw.Write(page.b.Bytes())
+		zw.Close()
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*k))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,96 @@
+package sudoku
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteBooklet(t *testing.T) {
+	var b bytes.Buffer
+	var sudokus []Sudoku
+
+	for i := 0; i < 5; i++ {
+		sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+		sudoku.SetMetadata(Metadata{Difficulty: "Easy"})
+		sudokus = append(sudokus, sudoku)
+	}
+
+	if err := WriteBooklet(&b, sudokus, BookletOptions{Title: "Weekly (Sudoku)"}); err != nil {
+		t.Fatalf("Sudoku: Can't write booklet: %v", err)
+	}
+
+	data := b.Bytes()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("Sudoku: Booklet is not a PDF document.")
+	}
+
+	// Two pages of puzzles and one of answers.
+	if !bytes.Contains(data, []byte("/Count 3")) {
+		t.Errorf("Sudoku: Booklet should have 3 pages.")
+	}
+
+	// Every entry of the cross-reference table must point to its object.
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n `).FindAllSubmatch(data, -1)
+
+	for k, match := range xref {
+		offset, _ := strconv.Atoi(string(match[1]))
+
+		if !bytes.HasPrefix(data[offset:], []byte(fmt.Sprintf("%d 0 obj", k+1))) {
+			t.Errorf("Sudoku: Cross-reference of object %d is wrong.", k+1)
+		}
+	}
+
+	// Read the text of every page.
+	var text strings.Builder
+	streams := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1)
+
+	for _, match := range streams {
+		length, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+
+		r, err := zlib.NewReader(bytes.NewReader(data[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("Sudoku: Invalid page content: %v", err)
+		}
+
+		content, _ := io.ReadAll(r)
+		text.Write(content)
+	}
+
+	for _, s := range []string{`(Weekly \(Sudoku\))`, `(Puzzle 1 \(Easy\))`, `(Puzzle 5 \(Easy\))`, "(Answers)", "(Page 3)"} {
+		if !strings.Contains(text.String(), s) {
+			t.Errorf("Sudoku: Booklet doesn't contain %s", s)
+		}
+	}
+
+	// Wrong arguments and unsolvable puzzles return an error.
+	if err := WriteBooklet(&b, sudokus, BookletOptions{PerPage: 3}); err == nil {
+		t.Errorf("Sudoku: Accepts 3 puzzles per page.")
+	}
+
+	sudokus[0].SetInitialValue(0, 2, 5)
+	if err := WriteBooklet(&b, sudokus, BookletOptions{}); err == nil {
+		t.Errorf("Sudoku: Writes answers of an unsolvable puzzle.")
+	}
+}
+
+func TestEscapePDF(t *testing.T) {
+	tests := map[string]string{
+		"Puzzle (1)":   `Puzzle \(1\)`,
+		"Café":         `Caf\351`,
+		"“Hard” – 5 €": `\223Hard\224 \226 5 \200`,
+		"\u0093数独":     "???",
+	}
+
+	for input, expected := range tests {
+		if output := escapePDF(input); output != expected {
+			t.Errorf("Sudoku: Escaped %q as %q instead of %q", input, output, expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package sudoku
+
+import (
+	"image"       // Raster images.
+	"image/color" // Colours of the image.
+	"image/draw"  // Filling rectangles.
+	"image/png"   // PNG encoding.
+	"io"          // Writers.
+)
+
+// A 5x7 bitmap font for the digits, each row of a glyph is a byte whose five
+// lowest bits are the pixels from left to right.
+var digitGlyphs = [10][7]uint8{
+	{0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	{0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	{0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	{0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	{0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	{0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	{0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	{0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+}
+
+// ImageOptions configures @RenderImage and @RenderPNG. Colours left nil take
+// the default values.
+type ImageOptions struct {
+	// The width and height of the image in pixels, 450 if zero.
+	Size int
+
+	Background color.Color // White by default.
+	Grid       color.Color // Black by default.
+	Given      color.Color // Black by default.
+	Entry      color.Color // Blue by default.
+	Candidate  color.Color // Grey by default.
+
+	// Background of the cells which repeat a value of their row, column or
+	// block. Light red by default.
+	Conflict color.Color
+
+	// Don't mark the cells in conflict.
+	HideConflicts bool
+
+	// The background colour of some cells. Cells with a nil colour are not
+	// highlighted.
+	Highlights map[Cell]color.Color
+}
+
+// Returns c, or def if c is nil.
+func colorOr(c, def color.Color) color.Color {
+	if c == nil {
+		return def
+	}
+
+	return c
+}
+
+// Draws the glyph of the digit scaled by scale, with its top left corner on
+// (x, y).
+func drawDigit(img *image.RGBA, digit, x, y, scale int, c color.Color) {
+	src := image.NewUniform(c)
+
+	for row, bits := range digitGlyphs[digit] {
+		for col := 0; col < 5; col++ {
+			if bits&(1<<(4-col)) != 0 {
+				rect := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, rect, src, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// Draws the digit centred on the square of the given side with its top left
+// corner on (x, y), as big as height allows it. The glyph is drawn unscaled
+// when height is too small for it but the square is not, and nothing is drawn
+// when the square can't hold it.
+func drawCentredDigit(img *image.RGBA, digit, x, y, side, height int, c color.Color) {
+	scale := height / 7
+
+	if scale < 1 {
+		if side < 7 {
+			return
+		}
+
+		scale = 1
+	}
+
+	drawDigit(img, digit, x+(side-5*scale)/2, y+(side-7*scale)/2, scale, c)
+}
+
+// RenderImage draws the sudoku as a raster image: the grid with thick borders
+// between blocks, the initial values and entered values in their colours, the
+// candidates of empty cells when the cells are big enough and the cells in
+// conflict highlighted. The digits use an embedded bitmap font, so no font
+// files are needed.
+func (sudoku *Sudoku) RenderImage(opts ImageOptions) *image.RGBA {
+	size := opts.Size
+	if size <= 0 {
+		size = 450
+	}
+
+	thin := size / 450
+	if thin < 1 {
+		thin = 1
+	}
+	thick := 3 * thin
+
+	cell := (size - thick) / 9
+	offset := (size - 9*cell) / 2
+
+	background := colorOr(opts.Background, color.White)
+	grid := colorOr(opts.Grid, color.Black)
+	given := colorOr(opts.Given, color.Black)
+	entry := colorOr(opts.Entry, color.RGBA{0x1a, 0x5f, 0xb4, 0xff})
+	candidate := colorOr(opts.Candidate, color.RGBA{0x80, 0x80, 0x80, 0xff})
+	conflict := colorOr(opts.Conflict, color.RGBA{0xff, 0xc0, 0xc0, 0xff})
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	var conflicts [9][9]bool
+	if !opts.HideConflicts {
+		conflicts = sudoku.conflictCells()
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			x := offset + j*cell
+			y := offset + i*cell
+			rect := image.Rect(x, y, x+cell, y+cell)
+
+			if c := opts.Highlights[Cell{i, j}]; c != nil {
+				draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+			}
+
+			if conflicts[i][j] {
+				draw.Draw(img, rect, image.NewUniform(conflict), image.Point{}, draw.Src)
+			}
+
+			if val := sudoku.values[i][j]; val != 0 {
+				c := entry
+				if sudoku.initialValues[i][j] != 0 {
+					c = given
+				}
+
+				drawCentredDigit(img, val, x, y, cell, cell*3/5, c)
+				continue
+			}
+
+			for _, mark := range maskDigits(sudoku.candidates[i][j]) {
+				drawCentredDigit(img, mark, x+((mark-1)%3)*cell/3, y+((mark-1)/3)*cell/3,
+					cell/3, cell/5, candidate)
+			}
+		}
+	}
+
+	// Thin lines between cells and thick lines between blocks.
+	src := image.NewUniform(grid)
+
+	for k := 0; k <= 9; k++ {
+		width := thin
+		if k%3 == 0 {
+			width = thick
+		}
+
+		pos := offset + k*cell - width/2
+		end := offset + 9*cell + thick/2 + thick%2
+
+		draw.Draw(img, image.Rect(offset-thick/2, pos, end, pos+width), src, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(pos, offset-thick/2, pos+width, end), src, image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+// RenderPNG draws the sudoku with @RenderImage and writes it as PNG.
+func (sudoku *Sudoku) RenderPNG(w io.Writer, opts ImageOptions) error {
+	return png.Encode(w, sudoku.RenderImage(opts))
+}
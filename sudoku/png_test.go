@@ -0,0 +1,100 @@
+package sudoku
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestRenderPNG(t *testing.T) {
+	var b bytes.Buffer
+
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	// The 5 on (1, 1) repeats the 5 on (0, 0) in the block.
+	sudoku.SetValue(1, 1, 5)
+	sudoku.SetCandidates(0, 2, []int{1, 2, 4})
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	yellow := color.RGBA{0xff, 0xff, 0, 0xff}
+	opts := ImageOptions{Size: 300, Conflict: red, Highlights: map[Cell]color.Color{{4, 4}: yellow, {8, 8}: nil}}
+
+	if err := sudoku.RenderPNG(&b, opts); err != nil {
+		t.Fatalf("Sudoku: Can't render PNG: %v", err)
+	}
+
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't decode rendered PNG: %v", err)
+	}
+
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 300 {
+		t.Errorf("Sudoku: Image should be 300x300 but is %v", img.Bounds())
+	}
+
+	// Cell size is (300 - 3) / 9 = 33 with an offset of 1 pixel. Check a corner
+	// of each cell, away from the digit and the grid lines. The nil highlight
+	// of (8, 8) leaves it white.
+	tests := []struct {
+		cell  Cell
+		color color.Color
+	}{
+		{Cell{0, 0}, red},
+		{Cell{1, 1}, red},
+		{Cell{4, 4}, yellow},
+		{Cell{8, 8}, color.White},
+	}
+
+	for _, test := range tests {
+		x := 1 + test.cell.Column*33 + 4
+		y := 1 + test.cell.Row*33 + 4
+
+		if !sameColor(img.At(x, y), test.color) {
+			t.Errorf("Sudoku: Cell %v should be %v but is %v", test.cell, test.color, img.At(x, y))
+		}
+	}
+
+	// The given 5 on (0, 0) is drawn in black in the middle of its cell.
+	found := false
+	for x := 1; x < 34; x++ {
+		if sameColor(img.At(x, 17), color.Black) && x > 4 && x < 30 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Sudoku: The initial value on (0, 0) was not drawn.")
+	}
+
+	// Digits are still drawn on small images. Cell size is (100 - 3) / 9 = 10
+	// with an offset of 5 pixels.
+	small := sudoku.RenderImage(ImageOptions{Size: 100})
+	found = false
+
+	for x := 7; x < 13; x++ {
+		for y := 7; y < 13; y++ {
+			if sameColor(small.At(x, y), color.Black) {
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("Sudoku: The initial value on (0, 0) was not drawn on a small image.")
+	}
+
+	// Conflicts are not drawn when hidden.
+	opts.HideConflicts = true
+	if img := sudoku.RenderImage(opts); sameColor(img.At(5, 5), red) {
+		t.Errorf("Sudoku: Conflicts are drawn when hidden.")
+	}
+}
+
+// Returns true if both colours are the same.
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
@@ -0,0 +1,155 @@
+package sudoku
+
+import (
+	"strings" // Comparing difficulties.
+)
+
+// The difficulty levels of a puzzle, from the easiest to the hardest.
+const (
+	// Solved with full houses, naked singles and hidden singles.
+	Easy = "Easy"
+
+	// Needs pointing or claiming as well.
+	Medium = "Medium"
+
+	// Needs naked pairs or hidden pairs.
+	Hard = "Hard"
+
+	// Can't be solved with the techniques of the logical solver.
+	Expert = "Expert"
+)
+
+// The difficulty levels in increasing order.
+var difficulties = []string{Easy, Medium, Hard, Expert}
+
+// The difficulty level each technique belongs to, and the points it adds to
+// the score of a rating every time it's used.
+var techniqueLevels = map[string]struct {
+	difficulty string
+	points     int
+}{
+	FullHouse:    {Easy, 1},
+	HiddenSingle: {Easy, 2},
+	NakedSingle:  {Easy, 4},
+	Pointing:     {Medium, 10},
+	Claiming:     {Medium, 10},
+	NakedPair:    {Hard, 15},
+	HiddenPair:   {Hard, 20},
+}
+
+// The points added to the score of a rating for every cell the logical solver
+// can't fill.
+const expertPoints = 50
+
+// Rating is the difficulty of a puzzle, measured by solving it with the
+// logical solver used by @NextHint.
+type Rating struct {
+	// Easy, Medium, Hard or Expert.
+	Difficulty string `json:"difficulty"`
+
+	// The hardest technique needed, empty if the puzzle was already filled.
+	Hardest string `json:"hardest,omitempty"`
+
+	// The number of deductions made by the logical solver.
+	Steps int `json:"steps"`
+
+	// The sum of the points of every deduction, higher is harder.
+	Score int `json:"score"`
+}
+
+// Returns the difficulty level written in any case, like "easy", or the text
+// itself if it's not a difficulty.
+func ParseDifficulty(text string) string {
+	for _, difficulty := range difficulties {
+		if strings.EqualFold(text, difficulty) {
+			return difficulty
+		}
+	}
+
+	return text
+}
+
+// Returns the position of the difficulty in the list of levels, -1 if it's
+// unknown.
+func difficultyIndex(difficulty string) int {
+	for k, level := range difficulties {
+		if level == difficulty {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// Applies the hint to the values and candidates. A placed value is removed from
+// the candidates of its row, column and block.
+func applyStep(values *[9][9]int, candidates *[9][9]uint16, hint Hint) {
+	for _, elimination := range hint.Eliminations {
+		candidates[elimination.Cell.Row][elimination.Cell.Column] &^= 1 << elimination.Value
+	}
+
+	if hint.Placement == nil {
+		return
+	}
+
+	x, y, val := hint.Placement.Cell.Row, hint.Placement.Cell.Column, hint.Placement.Value
+	values[x][y] = val
+	candidates[x][y] = 0
+
+	for k := 0; k < 9; k++ {
+		candidates[x][k] &^= 1 << val
+		candidates[k][y] &^= 1 << val
+		candidates[(x/3)*3+k/3][(y/3)*3+k%3] &^= 1 << val
+	}
+}
+
+// Rate returns the difficulty of the puzzle given by the initial values. The
+// values and pencil marks of the player are ignored. An error is returned if
+// the puzzle doesn't have a unique solution.
+func (sudoku *Sudoku) Rate() (Rating, error) {
+	var rating Rating
+
+	if _, err := sudoku.uniqueSolution(); err != nil {
+		return rating, err
+	}
+
+	puzzle := Sudoku{values: sudoku.initialValues, initialValues: sudoku.initialValues,
+		constraints: sudoku.constraints, solution: sudoku.solution}
+	values := puzzle.values
+	candidates := puzzle.logicCandidates()
+
+	rating.Difficulty = Easy
+
+	for {
+		hint, ok := findStep(&values, &candidates)
+		if !ok {
+			break
+		}
+
+		applyStep(&values, &candidates, hint)
+
+		level := techniqueLevels[hint.Technique]
+
+		rating.Steps++
+		rating.Score += level.points
+
+		if difficultyIndex(level.difficulty) > difficultyIndex(rating.Difficulty) {
+			rating.Difficulty = level.difficulty
+		}
+
+		if level.points > techniqueLevels[rating.Hardest].points {
+			rating.Hardest = hint.Technique
+		}
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if values[i][j] == 0 {
+				rating.Difficulty = Expert
+				rating.Score += expertPoints
+			}
+		}
+	}
+
+	return rating, nil
+}
@@ -0,0 +1,52 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		puzzle     string
+		difficulty string
+		hardest    string
+	}{
+		{"530070000600195000098000060800060003400803001700020006060000280000419005000080079", Easy, NakedSingle},
+		{"400000938032094100095300240370609004529001673604703090957008300003900400240030709", Medium, Pointing},
+		{"800000000003600000070090200050007000000045700000100030001000068008500010090000400", Expert, ""},
+	}
+
+	for _, test := range tests {
+		sudoku, _ := Parse(test.puzzle)
+
+		// The entries of the player must not matter.
+		sudoku.SetValue(0, 2, 4)
+
+		rating, err := sudoku.Rate()
+		if err != nil {
+			t.Fatalf("Sudoku: Can't rate %s: %v", test.puzzle, err)
+		}
+
+		if rating.Difficulty != test.difficulty {
+			t.Errorf("Sudoku: %s should be %s but is %s", test.puzzle, test.difficulty, rating.Difficulty)
+		}
+
+		if rating.Hardest != test.hardest {
+			t.Errorf("Sudoku: Hardest technique of %s should be %q but is %q", test.puzzle, test.hardest, rating.Hardest)
+		}
+	}
+
+	// Constraints are kept while rating.
+	sudoku, _ := Parse("030070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	sudoku.AddConstraint(Constraint{Cage, []Cell{{0, 0}, {0, 1}}, 8})
+
+	if rating, err := sudoku.Rate(); err != nil || rating.Difficulty != Easy {
+		t.Errorf("Sudoku: Constrained puzzle should be Easy but is %s (%v)", rating.Difficulty, err)
+	}
+
+	// Puzzles with many solutions can't be rated.
+	sudoku = Sudoku{}
+
+	if _, err := sudoku.Rate(); err == nil {
+		t.Errorf("Sudoku: Rated a puzzle with many solutions.")
+	}
+}
@@ -0,0 +1,257 @@
+package sudoku
+
+import (
+	"strings" // String manipulation.
+)
+
+// The modes of @Renderer.
+const (
+	// A grid drawn with Unicode box characters, as returned by @ToString.
+	BoxMode = iota
+
+	// A compact grid drawn with ASCII characters, for logs.
+	ASCIIMode
+
+	// The 81 character line read by @Parse.
+	LineMode
+
+	// A 27x27 grid where every cell shows its candidates in a 3x3 square, and
+	// its value in the centre if it's filled.
+	CandidateMode
+)
+
+// The ANSI escape codes used by @Renderer when Color is set.
+const (
+	ansiReset    = "\x1b[0m"
+	ansiGiven    = "\x1b[1m"
+	ansiEntry    = "\x1b[34m"
+	ansiConflict = "\x1b[1;31m"
+	ansiCursor   = "\x1b[7m"
+	ansiMarked   = "\x1b[43m"
+)
+
+// Renderer writes a sudoku as text in one of several modes. The zero value
+// renders like @ToString.
+type Renderer struct {
+	// BoxMode, ASCIIMode, LineMode or CandidateMode.
+	Mode int
+
+	// The character of the empty cells, '.' if zero. Use ' ' to leave them
+	// blank.
+	Empty rune
+
+	// Colour the values with ANSI escape codes: initial values in bold,
+	// entered values in blue and values in conflict in red. Only used by
+	// BoxMode and CandidateMode.
+	Color bool
+
+	// The cell under the cursor of an interactive front-end, nil if there is
+	// none. BoxMode draws it between brackets, and both BoxMode and
+	// CandidateMode draw it in reverse video when Color is set.
+	Cursor *Cell
+
+	// Cells drawn with a yellow background when Color is set, like the cells
+	// of a hint. Only used by BoxMode and CandidateMode.
+	Marked []Cell
+}
+
+// Returns a horizontal line of the grid, (fill) is the part above or below a
+// cell, (thin) is placed between cells and (thick) between blocks.
+func gridLine(left, fill, thin, thick, right string) string {
+	line := left
+
+	for j := 0; j < 9; j++ {
+		line += fill
+
+		if j == 8 {
+			line += right
+		} else if (j+1)%3 == 0 {
+			line += thick
+		} else {
+			line += thin
+		}
+	}
+
+	return line + "\n"
+}
+
+// The lines of the grid drawn by BoxMode and CandidateMode.
+var (
+	boxTop    = gridLine("╔", "───", "┬", "╦", "╗")
+	boxThin   = gridLine("├", "───", "┼", "┼", "┤")
+	boxThick  = gridLine("╠", "───", "┼", "╬", "╣")
+	boxBottom = gridLine("╚", "───", "┴", "╩", "╝")
+)
+
+// Render returns the sudoku as text in the mode of the renderer.
+func (renderer Renderer) Render(sudoku *Sudoku) string {
+	if renderer.Empty == 0 {
+		renderer.Empty = '.'
+	}
+
+	switch renderer.Mode {
+	case ASCIIMode:
+		return renderer.renderASCII(sudoku)
+	case LineMode:
+		return renderer.renderLine(sudoku)
+	case CandidateMode:
+		return renderer.renderBox(sudoku, 3)
+	default:
+		return renderer.renderBox(sudoku, 1)
+	}
+}
+
+// Returns the character of the value, or the empty character for 0.
+func (renderer Renderer) char(val int) string {
+	if val == 0 {
+		return string(renderer.Empty)
+	}
+
+	return string(rune('0' + val))
+}
+
+// Returns the text of the value on the row x and column y, coloured if the
+// renderer uses colours.
+func (renderer Renderer) value(sudoku *Sudoku, conflicts *[9][9]bool, x, y int) string {
+	text := renderer.char(sudoku.values[x][y])
+
+	if !renderer.Color || sudoku.values[x][y] == 0 {
+		return text
+	}
+
+	switch {
+	case conflicts[x][y]:
+		return ansiConflict + text + ansiReset
+	case sudoku.initialValues[x][y] != 0:
+		return ansiGiven + text + ansiReset
+	default:
+		return ansiEntry + text + ansiReset
+	}
+}
+
+// Returns the three characters of the line (line) of the cell on the row x and
+// column y, where a cell is (height) lines tall.
+func (renderer Renderer) cellLine(sudoku *Sudoku, conflicts *[9][9]bool, x, y, line, height int) string {
+	if height == 1 || sudoku.values[x][y] != 0 {
+		if line != height/2 {
+			return "   "
+		}
+
+		return " " + renderer.value(sudoku, conflicts, x, y) + " "
+	}
+
+	text := ""
+	for val := 3*line + 1; val <= 3*line+3; val++ {
+		if sudoku.candidates[x][y]&(1<<val) != 0 {
+			text += renderer.char(val)
+		} else {
+			text += " "
+		}
+	}
+
+	return text
+}
+
+// Returns the text in the given ANSI style, keeping the style after the resets
+// of the text.
+func ansiWrap(text, style string) string {
+	return style + strings.ReplaceAll(text, ansiReset, ansiReset+style) + ansiReset
+}
+
+// Draws the grid with Unicode box characters, where every cell is (height)
+// lines tall.
+func (renderer Renderer) renderBox(sudoku *Sudoku, height int) string {
+	var b strings.Builder
+	var conflicts [9][9]bool
+
+	var marked [9][9]bool
+
+	if renderer.Color {
+		conflicts = sudoku.conflictCells()
+
+		for _, cell := range renderer.Marked {
+			if cell.Row >= 0 && cell.Row <= 8 && cell.Column >= 0 && cell.Column <= 8 {
+				marked[cell.Row][cell.Column] = true
+			}
+		}
+	}
+
+	b.WriteString(boxTop)
+
+	for i := 0; i < 9; i++ {
+		for line := 0; line < height; line++ {
+			for j := 0; j < 9; j++ {
+				text := renderer.cellLine(sudoku, &conflicts, i, j, line, height)
+				cursor := renderer.Cursor != nil && *renderer.Cursor == Cell{i, j}
+
+				if cursor && height == 1 {
+					text = "[" + text[1:len(text)-1] + "]"
+				}
+
+				if marked[i][j] {
+					text = ansiWrap(text, ansiMarked)
+				}
+
+				if cursor && renderer.Color {
+					text = ansiWrap(text, ansiCursor)
+				}
+
+				b.WriteString("│")
+				b.WriteString(text)
+			}
+
+			b.WriteString("│\n")
+		}
+
+		switch {
+		case i == 8:
+			b.WriteString(boxBottom)
+		case (i+1)%3 == 0:
+			b.WriteString(boxThick)
+		default:
+			b.WriteString(boxThin)
+		}
+	}
+
+	return b.String()
+}
+
+// Draws a compact grid with ASCII characters and lines only between blocks.
+func (renderer Renderer) renderASCII(sudoku *Sudoku) string {
+	var b strings.Builder
+
+	border := "+-------+-------+-------+\n"
+
+	for i := 0; i < 9; i++ {
+		if i%3 == 0 {
+			b.WriteString(border)
+		}
+
+		for j := 0; j < 9; j++ {
+			if j%3 == 0 {
+				b.WriteString("| ")
+			}
+
+			b.WriteString(renderer.char(sudoku.values[i][j]) + " ")
+		}
+
+		b.WriteString("|\n")
+	}
+
+	b.WriteString(border)
+
+	return b.String()
+}
+
+// Writes all the values in a single line.
+func (renderer Renderer) renderLine(sudoku *Sudoku) string {
+	var b strings.Builder
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			b.WriteString(renderer.char(sudoku.values[i][j]))
+		}
+	}
+
+	return b.String()
+}
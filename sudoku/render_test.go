@@ -0,0 +1,84 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	line := "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	sudoku, _ := Parse(line)
+
+	if out := (Renderer{Mode: LineMode}).Render(&sudoku); out != line {
+		t.Errorf("Sudoku: Line should be %s but is %s", line, out)
+	}
+
+	if out := (Renderer{Mode: LineMode, Empty: '0'}).Render(&sudoku); out != strings.ReplaceAll(line, ".", "0") {
+		t.Errorf("Sudoku: Line with zeros is %s", out)
+	}
+
+	ascii := `+-------+-------+-------+
+| 5 3 . | . 7 . | . . . |
+| 6 . . | 1 9 5 | . . . |
+| . 9 8 | . . . | . 6 . |
++-------+-------+-------+
+| 8 . . | . 6 . | . . 3 |
+| 4 . . | 8 . 3 | . . 1 |
+| 7 . . | . 2 . | . . 6 |
++-------+-------+-------+
+| . 6 . | . . . | 2 8 . |
+| . . . | 4 1 9 | . . 5 |
+| . . . | . 8 . | . 7 9 |
++-------+-------+-------+
+`
+
+	if out := (Renderer{Mode: ASCIIMode}).Render(&sudoku); out != ascii {
+		t.Errorf("Sudoku: ASCII grid should be\n%s\nbut is\n%s", ascii, out)
+	}
+
+	// The box grid has the same lines as before, with blank empty cells.
+	box := (Renderer{Empty: ' '}).Render(&sudoku)
+	lines := strings.Split(box, "\n")
+
+	if len(lines) != 20 || lines[0] != "╔───┬───┬───╦───┬───┬───╦───┬───┬───╗" ||
+		lines[1] != "│ 5 │ 3 │   │   │ 7 │   │   │   │   │" ||
+		lines[6] != "╠───┼───┼───╬───┼───┼───╬───┼───┼───╣" ||
+		lines[18] != "╚───┴───┴───╩───┴───┴───╩───┴───┴───╝" {
+		t.Errorf("Sudoku: Unexpected box grid\n%s", box)
+	}
+
+	// The cursor is drawn between brackets.
+	lines = strings.Split((Renderer{Empty: ' ', Cursor: &Cell{0, 1}}).Render(&sudoku), "\n")
+
+	if lines[1] != "│ 5 │[3]│   │   │ 7 │   │   │   │   │" {
+		t.Errorf("Sudoku: Unexpected cursor line %s", lines[1])
+	}
+
+	// Candidate cells are three lines tall.
+	sudoku.SetCandidates(0, 2, []int{1, 2, 4, 9})
+	lines = strings.Split((Renderer{Mode: CandidateMode}).Render(&sudoku), "\n")
+
+	if len(lines) != 38 || lines[1] != "│   │   │12 │   │   │   │   │   │   │" ||
+		lines[2] != "│ 5 │ 3 │4  │   │ 7 │   │   │   │   │" ||
+		lines[3] != "│   │   │  9│   │   │   │   │   │   │" {
+		t.Errorf("Sudoku: Unexpected candidate grid\n%s", strings.Join(lines, "\n"))
+	}
+
+	// Colours distinguish givens, entries and conflicts.
+	sudoku.SetValue(0, 2, 4)
+	sudoku.SetValue(0, 3, 5)
+	out := (Renderer{Color: true}).Render(&sudoku)
+
+	for _, s := range []string{ansiGiven + "3" + ansiReset, ansiEntry + "4" + ansiReset, ansiConflict + "5" + ansiReset} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Sudoku: Coloured grid doesn't contain %q:\n%s", s, out)
+		}
+	}
+
+	// Marked cells keep their background after the colour of the value.
+	out = (Renderer{Color: true, Marked: []Cell{{0, 1}}}).Render(&sudoku)
+
+	if !strings.Contains(out, ansiMarked+" "+ansiGiven+"3"+ansiReset+ansiMarked+" "+ansiReset) {
+		t.Errorf("Sudoku: Marked cell is not highlighted:\n%s", out)
+	}
+}
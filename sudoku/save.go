@@ -0,0 +1,423 @@
+package sudoku
+
+import (
+	"encoding/json" // JSON encoding.
+	"errors"        // Error handling.
+	"fmt"           // Error formatting.
+	"os"            // Files.
+	"path/filepath" // File paths.
+	"runtime"       // Operating system.
+	"sort"          // Sorting the slots.
+	"strings"       // String manipulation.
+	"time"          // Elapsed time.
+)
+
+// The version of the save file written by @Game.MarshalJSON. Every version
+// ever written must still be accepted by @Game.UnmarshalJSON.
+const saveVersion = 1
+
+// A game is saved as the following object.
+//
+//	{
+//	  "version": 1,
+//	  "sudoku": {"version": 1, "givens": "...", "values": "...", ...},
+//	  "undo": [[{"row": 0, "column": 2, "after": 4}], ...],
+//	  "redo": [...],
+//	  "elapsed": 61000,
+//	  "completed": "2020-01-01T12:00:00Z",
+//	  "hints": 1,
+//	  "mistakes": 2,
+//	  "mistakeLimit": 3,
+//	  "log": {"version": 1, "puzzle": {...}, "actions": "..."}
+//	}
+//
+// "sudoku" is the JSON form of the sudoku being played, see @jsonSudoku, which
+// holds its givens, values, candidates and variant constraints. "undo" and
+// "redo" are the steps of the history, oldest first, and every step lists the
+// cells it changes with their values and candidates before and after it;
+// zeros and empty candidates are omitted. "elapsed" is the time played in
+// milliseconds and "completed" the completion time, omitted if the game isn't
+// complete. "log" is the log of the actions of the player, see @jsonMoveLog,
+// omitted if the game isn't logged. "version" is the version of this schema.
+type jsonGame struct {
+	Version      int            `json:"version"`
+	Sudoku       Sudoku         `json:"sudoku"`
+	Undo         [][]jsonChange `json:"undo,omitempty"`
+	Redo         [][]jsonChange `json:"redo,omitempty"`
+	Elapsed      int64          `json:"elapsed"`
+	Completed    *time.Time     `json:"completed,omitempty"`
+	Hints        int            `json:"hints,omitempty"`
+	Mistakes     int            `json:"mistakes,omitempty"`
+	MistakeLimit int            `json:"mistakeLimit,omitempty"`
+	Log          *MoveLog       `json:"log,omitempty"`
+}
+
+// The change of a cell in a step of the history, see @cellChange.
+type jsonChange struct {
+	Row              int    `json:"row"`
+	Column           int    `json:"column"`
+	Before           int    `json:"before,omitempty"`
+	After            int    `json:"after,omitempty"`
+	BeforeCandidates string `json:"beforeCandidates,omitempty"`
+	AfterCandidates  string `json:"afterCandidates,omitempty"`
+}
+
+// Returns the candidates of the bitmask as a string of digits.
+func maskString(mask uint16) string {
+	var b strings.Builder
+
+	for _, val := range maskDigits(mask) {
+		b.WriteRune(rune('0' + val))
+	}
+
+	return b.String()
+}
+
+// Returns the bitmask of the candidates given as a string of digits.
+func parseMask(s string) (uint16, error) {
+	var mask uint16
+
+	for _, r := range s {
+		if r < '1' || r > '9' {
+			return 0, fmt.Errorf("Sudoku: Invalid candidate %q.", r)
+		}
+
+		mask |= 1 << (r - '0')
+	}
+
+	return mask, nil
+}
+
+// Encodes the steps of a history.
+func encodeSteps(steps [][]cellChange) [][]jsonChange {
+	var result [][]jsonChange
+
+	for _, step := range steps {
+		changes := make([]jsonChange, len(step))
+
+		for k, change := range step {
+			changes[k] = jsonChange{
+				Row:              change.cell.Row,
+				Column:           change.cell.Column,
+				Before:           change.beforeValue,
+				After:            change.afterValue,
+				BeforeCandidates: maskString(change.beforeCandidates),
+				AfterCandidates:  maskString(change.afterCandidates),
+			}
+		}
+
+		result = append(result, changes)
+	}
+
+	return result
+}
+
+// Decodes the steps of a history. The cells changed must not hold a given.
+func decodeSteps(steps [][]jsonChange, sudoku *Sudoku) ([][]cellChange, error) {
+	var result [][]cellChange
+
+	for _, step := range steps {
+		changes := make([]cellChange, len(step))
+
+		for k, change := range step {
+			if change.Row < 0 || change.Row > 8 || change.Column < 0 || change.Column > 8 {
+				return nil, errors.New("Sudoku: Invalid cell in history.")
+			}
+
+			if sudoku.initialValues[change.Row][change.Column] != 0 {
+				return nil, errors.New("Sudoku: History changes an initial value.")
+			}
+
+			if change.Before < 0 || change.Before > 9 || change.After < 0 || change.After > 9 {
+				return nil, errors.New("Sudoku: Invalid value in history.")
+			}
+
+			before, err := parseMask(change.BeforeCandidates)
+			if err != nil {
+				return nil, err
+			}
+
+			after, err := parseMask(change.AfterCandidates)
+			if err != nil {
+				return nil, err
+			}
+
+			changes[k] = cellChange{Cell{change.Row, change.Column}, change.Before, change.After, before, after}
+		}
+
+		result = append(result, changes)
+	}
+
+	return result, nil
+}
+
+// MarshalJSON encodes the game as described in @jsonGame.
+func (game *Game) MarshalJSON() ([]byte, error) {
+	doc := jsonGame{
+		Version:      saveVersion,
+		Sudoku:       game.sudoku,
+		Undo:         encodeSteps(game.undo),
+		Redo:         encodeSteps(game.redo),
+		Elapsed:      game.Elapsed().Milliseconds(),
+		Hints:        game.hints,
+		Mistakes:     game.mistakes,
+		MistakeLimit: game.mistakeLimit,
+		Log:          game.moves,
+	}
+
+	if game.IsComplete() {
+		doc.Completed = &game.completed
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a game written by @MarshalJSON with the current or any
+// previous version of the schema. The timer of the loaded game runs with the
+// system clock unless the game is complete or lost; the autosave is not
+// loaded.
+func (game *Game) UnmarshalJSON(data []byte) error {
+	var doc jsonGame
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc.Version > saveVersion {
+		return fmt.Errorf("Sudoku: Unsupported save version %d.", doc.Version)
+	}
+
+	undo, err := decodeSteps(doc.Undo, &doc.Sudoku)
+	if err != nil {
+		return err
+	}
+
+	redo, err := decodeSteps(doc.Redo, &doc.Sudoku)
+	if err != nil {
+		return err
+	}
+
+	result := NewGame(doc.Sudoku)
+	result.undo = undo
+	result.redo = redo
+	result.hints = doc.Hints
+	result.mistakes = doc.Mistakes
+	result.mistakeLimit = doc.MistakeLimit
+	result.moves = doc.Log
+	result.elapsed = time.Duration(doc.Elapsed) * time.Millisecond
+
+	if doc.Completed != nil {
+		result.completed = *doc.Completed
+	}
+
+	if result.IsComplete() || result.IsLost() {
+		result.started = time.Time{}
+	}
+
+	*game = *result
+
+	return nil
+}
+
+// Writes the data to the file on path so it either keeps its old content or
+// has the new one, even if the program crashes while writing. The data is
+// written to a temporary file on the same directory, which then replaces the
+// file, and the directory is synced so the rename survives a crash too.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := file.Name()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// Flushes the entries of the directory on path to disk. Windows can't sync
+// directories, so nothing is done there.
+func syncDir(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+
+	return dir.Close()
+}
+
+// Saves the game to the file on path, replacing it atomically.
+func (game *Game) SaveFile(path string) error {
+	data, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// Returns the game saved on the file on path by @SaveFile.
+func LoadGame(path string) (*Game, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	game := &Game{}
+	if err := json.Unmarshal(data, game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// Sets a function called after every step added to the history, or undone or
+// redone, like one which saves the game with @SaveFile. A nil function turns
+// the autosave off.
+func (game *Game) SetAutosave(save func(game *Game) error) {
+	game.autosave = save
+	game.autosaveError = nil
+}
+
+// Returns the error of the last call to the autosave function, nil if it
+// succeeded.
+func (game *Game) AutosaveError() error {
+	return game.autosaveError
+}
+
+// The extension of the files of a @SaveStore.
+const saveExtension = ".json"
+
+// SaveStore keeps saved games as files of a directory, one file per slot.
+type SaveStore struct {
+	Dir string
+}
+
+// Slot describes a saved game of a @SaveStore.
+type Slot struct {
+	Name     string
+	Modified time.Time
+}
+
+// Returns the path of the file of the name on the directory, or an error if
+// the name can't be used as a file name.
+func storeFile(dir, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.New("Sudoku: Invalid name.")
+	}
+
+	return filepath.Join(dir, name+saveExtension), nil
+}
+
+// Returns the path of the file of the slot, see @storeFile.
+func (store SaveStore) slotPath(name string) (string, error) {
+	return storeFile(store.Dir, name)
+}
+
+// Saves the game on the slot, creating the directory if needed.
+func (store SaveStore) Save(name string, game *Game) error {
+	path, err := store.slotPath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(store.Dir, 0755); err != nil {
+		return err
+	}
+
+	return game.SaveFile(path)
+}
+
+// Returns the game saved on the slot.
+func (store SaveStore) Load(name string) (*Game, error) {
+	path, err := store.slotPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadGame(path)
+}
+
+// Removes the slot.
+func (store SaveStore) Delete(name string) error {
+	path, err := store.slotPath(name)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Returns the slots of the store, the most recently saved first. A store whose
+// directory doesn't exist yet has no slots.
+func (store SaveStore) Slots() ([]Slot, error) {
+	entries, err := os.ReadDir(store.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []Slot
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, saveExtension) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		slots = append(slots, Slot{strings.TrimSuffix(name, saveExtension), info.ModTime()})
+	}
+
+	sort.SliceStable(slots, func(a, b int) bool {
+		return slots[a].Modified.After(slots[b].Modified)
+	})
+
+	return slots, nil
+}
+
+// Returns an autosave function, see @SetAutosave, which saves the game on the
+// slot after every move.
+func (store SaveStore) Autosave(name string) func(game *Game) error {
+	return func(game *Game) error {
+		return store.Save(name, game)
+	}
+}
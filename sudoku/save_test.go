@@ -0,0 +1,126 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveGame(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	sudoku.AddConstraint(Constraint{Cage, []Cell{{0, 2}, {0, 3}}, 10})
+
+	clock := &fakeClock{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+	game := NewGame(sudoku)
+	game.SetClock(clock)
+	game.SetMistakeLimit(3)
+
+	game.SetValue(0, 2, 1)
+	game.SetValue(0, 2, 4)
+	game.FillCandidates()
+	game.ToggleCandidate(0, 3, 2)
+	game.Undo()
+	game.NextHint()
+	clock.Advance(90 * time.Second)
+
+	path := filepath.Join(t.TempDir(), "game.json")
+
+	if err := game.SaveFile(path); err != nil {
+		t.Fatalf("Sudoku: Can't save game: %v", err)
+	}
+
+	loaded, err := LoadGame(path)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't load game: %v", err)
+	}
+
+	// The cached solution is not saved.
+	expected := game.GetSudoku()
+	expected.solution = nil
+	expected.solutionError = nil
+
+	if !reflect.DeepEqual(loaded.GetSudoku(), expected) {
+		t.Errorf("Sudoku: Loaded\n%v\ninstead of\n%v", loaded.sudoku.ToString(), game.sudoku.ToString())
+	}
+
+	if !reflect.DeepEqual(loaded.undo, game.undo) || !reflect.DeepEqual(loaded.redo, game.redo) {
+		t.Errorf("Sudoku: History was not loaded.")
+	}
+
+	if loaded.Elapsed() < 90*time.Second || loaded.GetHints() != 1 || loaded.GetMistakes() != 1 || loaded.mistakeLimit != 3 {
+		t.Errorf("Sudoku: Loaded elapsed %v, %d hints and %d mistakes", loaded.Elapsed(), loaded.GetHints(), loaded.GetMistakes())
+	}
+
+	// The history keeps working after loading.
+	for loaded.Undo() {
+	}
+
+	if current := loaded.GetSudoku(); current.FilledCount() != sudoku.FilledCount() {
+		t.Errorf("Sudoku: Undoing the loaded history left %d filled cells", current.FilledCount())
+	}
+
+	// Newer versions can't be read.
+	var other Game
+	if err := json.Unmarshal([]byte(`{"version": 99}`), &other); err == nil {
+		t.Errorf("Sudoku: Loaded a newer version.")
+	}
+
+	// Histories which change the givens are rejected.
+	data := `{"version": 1, "sudoku": {"givens": "5", "values": "5"}, "undo": [[{"row": 0, "column": 0, "after": 1}]]}`
+	if err := json.Unmarshal([]byte(data), &other); err == nil {
+		t.Errorf("Sudoku: Loaded a history which changes a given.")
+	}
+}
+
+func TestSaveStore(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	store := SaveStore{filepath.Join(t.TempDir(), "saves")}
+
+	if slots, err := store.Slots(); err != nil || len(slots) != 0 {
+		t.Errorf("Sudoku: New store has slots %v (%v)", slots, err)
+	}
+
+	game := NewGame(sudoku)
+	game.SetAutosave(store.Autosave("first"))
+	game.SetValue(0, 2, 4)
+
+	if game.AutosaveError() != nil {
+		t.Fatalf("Sudoku: Autosave failed: %v", game.AutosaveError())
+	}
+
+	loaded, err := store.Load("first")
+	if err != nil {
+		t.Fatalf("Sudoku: Can't load autosave: %v", err)
+	}
+
+	if val, _ := loaded.sudoku.GetValue(0, 2); val != 4 {
+		t.Errorf("Sudoku: Autosave doesn't have the last move.")
+	}
+
+	store.Save("second", game)
+
+	// The temporary files of atomic writes are not slots.
+	os.WriteFile(filepath.Join(store.Dir, ".second.json.123.tmp"), nil, 0644)
+
+	slots, _ := store.Slots()
+	if len(slots) != 2 {
+		t.Fatalf("Sudoku: Store should have 2 slots but has %v", slots)
+	}
+
+	if err := store.Delete("first"); err != nil {
+		t.Errorf("Sudoku: Can't delete slot: %v", err)
+	}
+
+	if slots, _ := store.Slots(); len(slots) != 1 || slots[0].Name != "second" {
+		t.Errorf("Sudoku: Store should only have the second slot but has %v", slots)
+	}
+
+	for _, name := range []string{"", "../escape", ".hidden", "a/b"} {
+		if err := store.Save(name, game); err == nil {
+			t.Errorf("Sudoku: Saved on the invalid slot %q", name)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package sudoku
+
+import (
+	"context"       // Shutdown.
+	"encoding/json" // JSON bodies.
+	"errors"        // Error handling.
+	"net/http"      // HTTP server.
+	"strconv"       // Query parameters.
+	"time"          // Timeouts.
+)
+
+// ServerOptions configures @NewServer. The zero value uses the defaults of
+// every field.
+type ServerOptions struct {
+	// The time a request may take, 10 seconds if zero.
+	Timeout time.Duration
+
+	// The size of a request body in bytes, 64 KiB if zero.
+	MaxBodySize int64
+
+	// The first day of the archive of daily puzzles, see @Daily. The archive
+	// starts on 2024-01-01 if zero.
+	DailyStart time.Time
+
+	// The multiplayer sessions played on /play, which is only served if set.
+	Hub *Hub
+}
+
+// The error body of the API, like
+//
+//	{"error": {"code": "unprocessable", "message": "Sudoku: The puzzle has no solution."}}
+//
+// where the code is one of the following and the message comes from the
+// library.
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// The codes of the errors of the API.
+const (
+	errBadRequest       = "bad_request"
+	errTooLarge         = "too_large"
+	errNotFound         = "not_found"
+	errMethodNotAllowed = "method_not_allowed"
+	errConflict         = "conflict"
+	errUnprocessable    = "unprocessable"
+	errTimeout          = "timeout"
+)
+
+// The response of POST /hint: the hint and its text at the requested level.
+type hintResponse struct {
+	Hint
+	Text string `json:"text"`
+}
+
+// The HTTP API of the library.
+type server struct {
+	opts  ServerOptions
+	daily *Daily
+}
+
+// Returns the JSON of the error body.
+func errorBody(code, message string) []byte {
+	var body apiError
+	body.Error.Code = code
+	body.Error.Message = message
+
+	data, _ := json.Marshal(body)
+
+	return data
+}
+
+// Writes the value as a JSON body with the given status.
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		status, data = http.StatusInternalServerError, errorBody(errUnprocessable, err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// Writes an error body with the given status.
+func writeAPIError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(errorBody(code, err.Error()), '\n'))
+}
+
+// Returns a handler of the method which reads a sudoku from the body, if
+// body is set, and passes it to fn.
+func (s *server) handle(method string, body bool, fn func(w http.ResponseWriter, r *http.Request, sudoku *Sudoku)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeAPIError(w, http.StatusMethodNotAllowed, errMethodNotAllowed, errors.New("Sudoku: Method not allowed."))
+			return
+		}
+
+		var sudoku Sudoku
+
+		if body {
+			r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodySize)
+
+			if err := json.NewDecoder(r.Body).Decode(&sudoku); err != nil {
+				var tooLarge *http.MaxBytesError
+
+				if errors.As(err, &tooLarge) {
+					writeAPIError(w, http.StatusRequestEntityTooLarge, errTooLarge, errors.New("Sudoku: Request body too large."))
+				} else {
+					writeAPIError(w, http.StatusBadRequest, errBadRequest, err)
+				}
+
+				return
+			}
+		}
+
+		fn(w, r, &sudoku)
+	})
+}
+
+func (s *server) solve(w http.ResponseWriter, r *http.Request, sudoku *Sudoku) {
+	solved, err := sudoku.Solve()
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errUnprocessable, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, solved)
+}
+
+func (s *server) validate(w http.ResponseWriter, r *http.Request, sudoku *Sudoku) {
+	writeJSON(w, http.StatusOK, sudoku.Validate())
+}
+
+func (s *server) rate(w http.ResponseWriter, r *http.Request, sudoku *Sudoku) {
+	rating, err := sudoku.Rate()
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errUnprocessable, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rating)
+}
+
+func (s *server) hint(w http.ResponseWriter, r *http.Request, sudoku *Sudoku) {
+	level := 3
+
+	if text := r.URL.Query().Get("level"); text != "" {
+		var err error
+
+		if level, err = strconv.Atoi(text); err != nil || level < 1 || level > 3 {
+			writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Level must be 1, 2 or 3."))
+			return
+		}
+	}
+
+	hint, err := sudoku.NextHint()
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, errUnprocessable, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hintResponse{hint, hint.Text(level)})
+}
+
+func (s *server) generate(w http.ResponseWriter, r *http.Request, _ *Sudoku) {
+	query := r.URL.Query()
+	difficulty := ParseDifficulty(query.Get("difficulty"))
+	symmetry := query.Get("symmetry")
+	seed := time.Now().UnixNano()
+
+	if difficulty == "" {
+		difficulty = Easy
+	}
+
+	if symmetry == "" {
+		symmetry = Rotational
+	}
+
+	if text := query.Get("seed"); text != "" {
+		var err error
+
+		if seed, err = strconv.ParseInt(text, 10, 64); err != nil {
+			writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Invalid seed."))
+			return
+		}
+	}
+
+	// The context is done once the client leaves or the request times out, so
+	// the puzzle is no longer needed.
+	sudoku, err := NewGenerator(seed).GenerateContext(r.Context(), difficulty, symmetry)
+	if r.Context().Err() != nil {
+		writeAPIError(w, http.StatusServiceUnavailable, errTimeout, errors.New("Sudoku: The request took too long."))
+		return
+	} else if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sudoku)
+}
+
+func (s *server) dailyPuzzle(w http.ResponseWriter, r *http.Request, _ *Sudoku) {
+	query := r.URL.Query()
+	difficulty := ParseDifficulty(query.Get("difficulty"))
+	date := s.daily.today()
+
+	if difficulty == "" {
+		difficulty = Easy
+	}
+
+	if text := query.Get("date"); text != "" {
+		var err error
+
+		if date, err = time.Parse(dailyLayout, text); err != nil {
+			writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Invalid date."))
+			return
+		}
+	}
+
+	if difficultyIndex(difficulty) < 0 {
+		writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Unknown difficulty."))
+		return
+	}
+
+	sudoku, err := s.daily.Puzzle(r.Context(), date, difficulty)
+	if r.Context().Err() != nil {
+		writeAPIError(w, http.StatusServiceUnavailable, errTimeout, errors.New("Sudoku: The request took too long."))
+		return
+	} else if err != nil {
+		writeAPIError(w, http.StatusNotFound, errNotFound, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sudoku)
+}
+
+func (s *server) dailyArchive(w http.ResponseWriter, r *http.Request, _ *Sudoku) {
+	query := r.URL.Query()
+	before := s.daily.today().AddDate(0, 0, 1)
+	limit := 30
+
+	if text := query.Get("before"); text != "" {
+		var err error
+
+		if before, err = time.Parse(dailyLayout, text); err != nil {
+			writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Invalid date."))
+			return
+		}
+	}
+
+	if text := query.Get("limit"); text != "" {
+		var err error
+
+		if limit, err = strconv.Atoi(text); err != nil || limit < 1 || limit > 366 {
+			writeAPIError(w, http.StatusBadRequest, errBadRequest, errors.New("Sudoku: Limit must be between 1 and 366."))
+			return
+		}
+	}
+
+	dates := []string{}
+
+	for _, day := range s.daily.ArchivePage(before, limit) {
+		dates = append(dates, day.Format(dailyLayout))
+	}
+
+	writeJSON(w, http.StatusOK, dates)
+}
+
+// NewServer returns the HTTP handler of the JSON API of the library:
+//
+//	POST /solve          the sudoku solved, see @Solve
+//	POST /validate       the uniqueness, minimality and conflicts of the sudoku
+//	POST /rate           the difficulty of the sudoku, see @Rate
+//	POST /hint           the next hint, see @NextHint; ?level=1 to 3 for its text
+//	GET  /generate       a new puzzle; ?difficulty=, ?symmetry= and ?seed=
+//	GET  /daily          the puzzle of the day, see @Daily; ?difficulty= and ?date=
+//	GET  /daily/archive  the dates of the daily puzzles, newest first; ?limit= 1 to
+//	                     366, 30 by default, and ?before= the last date of the
+//	                     previous page
+//	POST /play           a new session of the sudoku, see @Hub; ?session= and ?mode=
+//	GET  /play           a WebSocket joining a session; ?session= and ?player=
+//
+// The bodies of the requests and responses are sudokus in the JSON form of
+// @jsonSudoku, and errors are written as described in @apiError. Every
+// endpoint but /play must answer within the timeout; WebSockets stay open for
+// as long as the game lasts.
+func NewServer(opts ServerOptions) http.Handler {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 64 << 10
+	}
+
+	if opts.DailyStart.IsZero() {
+		opts.DailyStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	s := &server{opts, NewDaily(opts.DailyStart)}
+	mux := http.NewServeMux()
+
+	mux.Handle("/solve", s.handle(http.MethodPost, true, s.solve))
+	mux.Handle("/validate", s.handle(http.MethodPost, true, s.validate))
+	mux.Handle("/rate", s.handle(http.MethodPost, true, s.rate))
+	mux.Handle("/hint", s.handle(http.MethodPost, true, s.hint))
+	mux.Handle("/generate", s.handle(http.MethodGet, false, s.generate))
+	mux.Handle("/daily", s.handle(http.MethodGet, false, s.dailyPuzzle))
+	mux.Handle("/daily/archive", s.handle(http.MethodGet, false, s.dailyArchive))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, errNotFound, errors.New("Sudoku: Unknown endpoint."))
+	})
+
+	timeout := string(errorBody(errTimeout, "Sudoku: The request took too long."))
+	handler := http.TimeoutHandler(mux, opts.Timeout, timeout)
+
+	if opts.Hub == nil {
+		return handler
+	}
+
+	// The writer of the timeout handler can't be hijacked, so WebSockets are
+	// served next to it.
+	root := http.NewServeMux()
+	root.Handle("/play", opts.Hub)
+	root.Handle("/", handler)
+
+	return root
+}
+
+// Serve runs the API of @NewServer on the address until the context is done,
+// and then shuts the server down gracefully, giving the requests in progress
+// the time of a request to finish.
+func Serve(ctx context.Context, addr string, opts ServerOptions) error {
+	handler := NewServer(opts)
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: opts.Timeout,
+		ReadTimeout:       opts.Timeout,
+		WriteTimeout:      2 * opts.Timeout,
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	return srv.Shutdown(shutdown)
+}
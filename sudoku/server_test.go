@@ -0,0 +1,201 @@
+package sudoku
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sends a request to the handler and decodes its JSON body into value.
+func request(t *testing.T, handler http.Handler, method, target, body string, value interface{}) int {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), value); err != nil {
+		t.Fatalf("Sudoku: %s %s returned %q: %v", method, target, recorder.Body.String(), err)
+	}
+
+	return recorder.Code
+}
+
+func TestServer(t *testing.T) {
+	handler := NewServer(ServerOptions{MaxBodySize: 1024, DailyStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	// Returns the JSON of a puzzle with the given givens.
+	body := func(grid string) string {
+		return `{"givens": "` + grid + `", "values": "` + grid + `"}`
+	}
+
+	puzzle := body("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	var solved Sudoku
+	if code := request(t, handler, "POST", "/solve", puzzle, &solved); code != http.StatusOK || !solved.IsComplete() {
+		t.Errorf("Sudoku: /solve returned %d and\n%v", code, solved.ToString())
+	}
+
+	var validation Validation
+	if code := request(t, handler, "POST", "/validate", puzzle, &validation); code != http.StatusOK || !validation.Valid || validation.Solutions != 1 {
+		t.Errorf("Sudoku: /validate returned %d and %+v", code, validation)
+	}
+
+	var rating Rating
+	if code := request(t, handler, "POST", "/rate", puzzle, &rating); code != http.StatusOK || rating.Difficulty != Easy {
+		t.Errorf("Sudoku: /rate returned %d and %+v", code, rating)
+	}
+
+	var hint hintResponse
+	if code := request(t, handler, "POST", "/hint?level=1", puzzle, &hint); code != http.StatusOK || hint.Technique == "" || !strings.HasPrefix(hint.Text, "Look for") {
+		t.Errorf("Sudoku: /hint returned %d and %+v", code, hint)
+	}
+
+	var generated Sudoku
+	if code := request(t, handler, "GET", "/generate?difficulty=medium&seed=3", "", &generated); code != http.StatusOK || generated.GetMetadata().Difficulty != Medium {
+		t.Errorf("Sudoku: /generate returned %d and\n%v", code, generated.ToString())
+	}
+
+	// Generation stops once the client leaves.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/generate?difficulty=expert", nil).WithContext(ctx))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Sudoku: Cancelled /generate returned %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/daily?date=2024-02-01", nil).WithContext(ctx))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Sudoku: Cancelled /daily returned %d", recorder.Code)
+	}
+
+	var daily Sudoku
+	if code := request(t, handler, "GET", "/daily?difficulty=hard", "", &daily); code != http.StatusOK || daily.GetMetadata().Date == "" {
+		t.Errorf("Sudoku: /daily returned %d and %+v", code, daily.GetMetadata())
+	}
+
+	var archive []string
+	if code := request(t, handler, "GET", "/daily/archive", "", &archive); code != http.StatusOK || len(archive) != 30 {
+		t.Errorf("Sudoku: /daily/archive returned %d and %v", code, archive)
+	}
+
+	if code := request(t, handler, "GET", "/daily/archive?before=2024-01-02&limit=5", "", &archive); code != http.StatusOK || len(archive) != 1 || archive[0] != "2024-01-01" {
+		t.Errorf("Sudoku: Last page of /daily/archive returned %d and %v", code, archive)
+	}
+
+	errors := []struct {
+		method string
+		target string
+		body   string
+		status int
+		code   string
+	}{
+		{"GET", "/solve", "", http.StatusMethodNotAllowed, errMethodNotAllowed},
+		{"POST", "/solve", "{", http.StatusBadRequest, errBadRequest},
+		{"POST", "/solve", body("55" + strings.Repeat(".", 79)), http.StatusUnprocessableEntity, errUnprocessable},
+		{"POST", "/rate", body("5" + strings.Repeat(".", 80)), http.StatusUnprocessableEntity, errUnprocessable},
+		{"POST", "/solve", `{"givens": "` + strings.Repeat(".", 2000) + `"}`, http.StatusRequestEntityTooLarge, errTooLarge},
+		{"POST", "/hint?level=7", puzzle, http.StatusBadRequest, errBadRequest},
+		{"GET", "/generate?difficulty=impossible", "", http.StatusBadRequest, errBadRequest},
+		{"GET", "/unknown", "", http.StatusNotFound, errNotFound},
+		{"GET", "/daily?date=1999-01-01", "", http.StatusNotFound, errNotFound},
+		{"GET", "/daily?date=yesterday", "", http.StatusBadRequest, errBadRequest},
+		{"GET", "/daily?difficulty=impossible", "", http.StatusBadRequest, errBadRequest},
+		{"GET", "/daily/archive?limit=0", "", http.StatusBadRequest, errBadRequest},
+		{"GET", "/daily/archive?before=soon", "", http.StatusBadRequest, errBadRequest},
+	}
+
+	for _, test := range errors {
+		var body apiError
+
+		if code := request(t, handler, test.method, test.target, test.body, &body); code != test.status || body.Error.Code != test.code || body.Error.Message == "" {
+			t.Errorf("Sudoku: %s %s returned %d and %+v", test.method, test.target, code, body)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- Serve(ctx, "127.0.0.1:0", ServerOptions{Timeout: time.Second})
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errs:
+		if err != nil && err != http.ErrServerClosed {
+			t.Errorf("Sudoku: Server didn't shut down cleanly: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Sudoku: Server didn't shut down.")
+	}
+}
+
+func TestServerDailyStart(t *testing.T) {
+	handler := NewServer(ServerOptions{})
+
+	var body apiError
+	if code := request(t, handler, "GET", "/daily?date=2023-12-31", "", &body); code != http.StatusNotFound {
+		t.Errorf("Sudoku: The default archive has the puzzle of 2023-12-31: %d", code)
+	}
+
+	var daily Sudoku
+	if code := request(t, handler, "GET", "/daily?date=2024-01-01", "", &daily); code != http.StatusOK || daily.GetMetadata().Date != "2024-01-01" {
+		t.Errorf("Sudoku: The default archive doesn't start on 2024-01-01: %d %+v", code, daily.GetMetadata())
+	}
+}
+
+func TestServerPlay(t *testing.T) {
+	handler := NewServer(ServerOptions{Timeout: time.Second, Hub: NewHub()})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	grid := "530070000600195000098000060800060003400803001700020006060000280000419005000080079"
+	puzzle := `{"givens": "` + grid + `", "values": "` + grid + `"}`
+
+	var created hubSession
+	if code := request(t, handler, "POST", "/play?session=table", puzzle, &created); code != http.StatusCreated || created.Mode != CoopMode {
+		t.Errorf("Sudoku: POST /play returned %d and %+v", code, created)
+	}
+
+	var body apiError
+	if code := request(t, handler, "POST", "/play?session=table", puzzle, &body); code != http.StatusConflict || body.Error.Code != errConflict {
+		t.Errorf("Sudoku: Created the same session twice: %d %+v", code, body)
+	}
+
+	if code := request(t, handler, "POST", "/play?session=other&mode=solo", puzzle, &body); code != http.StatusBadRequest {
+		t.Errorf("Sudoku: Created a session of an unknown mode: %d %+v", code, body)
+	}
+
+	// The connection can be hijacked, so /play is not behind the timeout.
+	ws, err := wsDial("ws" + strings.TrimPrefix(server.URL, "http") + "/play?session=table&player=ann")
+	if err != nil {
+		t.Fatalf("Sudoku: Can't join the session: %v", err)
+	}
+
+	defer ws.Close()
+
+	ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	data, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("Sudoku: Can't read the first event: %v", err)
+	}
+
+	var event SessionEvent
+	if json.Unmarshal(data, &event); event.Type != EventState {
+		t.Errorf("Sudoku: First event is %+v", event)
+	}
+}
@@ -0,0 +1,246 @@
+package sudoku
+
+import (
+	"errors"    // Error handling.
+	"math/rand" // Random order of the values.
+)
+
+// The state of a backtracking search: the grid being filled, the values used
+// on each row, column and block as bitmasks, and the constraints which apply
+// to each cell.
+type solver struct {
+	scratch     Sudoku
+	rows        [9]uint16
+	columns     [9]uint16
+	blocks      [9]uint16
+	constraints [9][9][]*Constraint
+	solutions   int
+	first       [9][9]int
+	limit       int
+
+	// Tries the values of each cell in a random order if set, see @Generator.
+	random *rand.Rand
+}
+
+// Returns a solver starting from the given grid, or false if the grid already
+// repeats a value in a row, column or block, or breaks a constraint.
+func newSolver(sudoku *Sudoku, grid [9][9]int) (*solver, bool) {
+	s := &solver{}
+	s.scratch.values = grid
+	s.scratch.constraints = sudoku.constraints
+
+	for k := range s.scratch.constraints {
+		constraint := &s.scratch.constraints[k]
+
+		for _, cell := range constraint.Cells {
+			s.constraints[cell.Row][cell.Column] = append(s.constraints[cell.Row][cell.Column], constraint)
+		}
+
+		if constraint.Violated(&s.scratch) {
+			return nil, false
+		}
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			val := grid[i][j]
+
+			if val == 0 {
+				continue
+			}
+
+			bit := uint16(1) << val
+			block := (i/3)*3 + j/3
+
+			if (s.rows[i]|s.columns[j]|s.blocks[block])&bit != 0 {
+				return nil, false
+			}
+
+			s.rows[i] |= bit
+			s.columns[j] |= bit
+			s.blocks[block] |= bit
+		}
+	}
+
+	return s, true
+}
+
+// Searches for solutions until the limit is reached, filling first with the
+// first one found.
+func (s *solver) search() {
+	bestI, bestJ := -1, -1
+	var bestMask uint16
+	bestCount := 10
+
+	// Continue on the empty cell with the fewest candidates.
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if s.scratch.values[i][j] != 0 {
+				continue
+			}
+
+			mask := ^(s.rows[i] | s.columns[j] | s.blocks[(i/3)*3+j/3]) & 0x3FE
+			count := 0
+
+			for m := mask; m != 0; m &= m - 1 {
+				count++
+			}
+
+			if count < bestCount {
+				bestI, bestJ, bestMask, bestCount = i, j, mask, count
+			}
+		}
+	}
+
+	if bestI < 0 {
+		if s.solutions == 0 {
+			s.first = s.scratch.values
+		}
+
+		s.solutions++
+		return
+	}
+
+	i, j, block := bestI, bestJ, (bestI/3)*3+bestJ/3
+	vals := maskDigits(bestMask)
+
+	if s.random != nil {
+		s.random.Shuffle(len(vals), func(a, b int) {
+			vals[a], vals[b] = vals[b], vals[a]
+		})
+	}
+
+	for _, val := range vals {
+		if s.solutions >= s.limit {
+			break
+		}
+
+		bit := uint16(1) << val
+
+		s.scratch.values[i][j] = val
+
+		violated := false
+		for _, constraint := range s.constraints[i][j] {
+			if constraint.Violated(&s.scratch) {
+				violated = true
+				break
+			}
+		}
+
+		if !violated {
+			s.rows[i] |= bit
+			s.columns[j] |= bit
+			s.blocks[block] |= bit
+
+			s.search()
+
+			s.rows[i] &^= bit
+			s.columns[j] &^= bit
+			s.blocks[block] &^= bit
+		}
+
+		s.scratch.values[i][j] = 0
+	}
+}
+
+// Counts the solutions of the puzzle given by the initial values, stopping once
+// limit solutions are found. Returns the count and the first solution found.
+func (sudoku *Sudoku) countSolutions(limit int) (int, [9][9]int) {
+	s, ok := newSolver(sudoku, sudoku.initialValues)
+
+	if !ok {
+		return 0, [9][9]int{}
+	}
+
+	s.limit = limit
+	s.search()
+
+	return s.solutions, s.first
+}
+
+// Returns the number of solutions of the puzzle given by the initial values,
+// stopping once limit solutions are found, like 2 to tell whether the solution
+// is unique, and the first of them as @Solve does. The sudoku is empty if
+// there is no solution.
+func (sudoku *Sudoku) CountSolutions(limit int) (int, Sudoku) {
+	count, solution := sudoku.countSolutions(limit)
+
+	if count == 0 {
+		return 0, Sudoku{}
+	}
+
+	result := *sudoku
+	result.values = solution
+	result.candidates = [9][9]uint16{}
+	result.constraints = sudoku.GetConstraints()
+
+	return count, result
+}
+
+// Solve returns a copy of the sudoku with every cell filled with a solution of
+// the puzzle given by its initial values, respecting its variant constraints.
+// The values entered by the player are ignored. An error is returned if the
+// puzzle has no solution.
+func (sudoku *Sudoku) Solve() (Sudoku, error) {
+	count, result := sudoku.CountSolutions(1)
+
+	if count == 0 {
+		return Sudoku{}, errors.New("Sudoku: The puzzle has no solution.")
+	}
+
+	return result, nil
+}
+
+// Returns the unique solution of the puzzle given by the initial values, or an
+// error if it has none or more than one. The solution is computed once and
+// kept in the sudoku until the initial values or the constraints change; it's
+// kept unexported so the player can't look at it. The returned array must not
+// be written, copies of the sudoku share it.
+func (sudoku *Sudoku) uniqueSolution() (*[9][9]int, error) {
+	if sudoku.solution != nil || sudoku.solutionError != nil {
+		return sudoku.solution, sudoku.solutionError
+	}
+
+	count, solution := sudoku.countSolutions(2)
+
+	switch count {
+	case 0:
+		sudoku.solutionError = errors.New("Sudoku: The puzzle has no solution.")
+	case 1:
+		sudoku.solution = &solution
+	default:
+		sudoku.solutionError = errors.New("Sudoku: The puzzle has more than one solution.")
+	}
+
+	return sudoku.solution, sudoku.solutionError
+}
+
+// Clears the solution computed by @uniqueSolution, after the initial values or
+// the constraints changed.
+func (sudoku *Sudoku) forgetSolution() {
+	sudoku.solution = nil
+	sudoku.solutionError = nil
+}
+
+// Returns the filled cells whose value is not the one of the solution, even if
+// they don't repeat any value yet. An error is returned if the puzzle doesn't
+// have a unique solution. The solution is kept in the sudoku for the next
+// calls, see @uniqueSolution.
+func (sudoku *Sudoku) Mistakes() ([]Cell, error) {
+	var cells []Cell
+
+	solution, err := sudoku.uniqueSolution()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if val := sudoku.values[i][j]; val != 0 && val != solution[i][j] {
+				cells = append(cells, Cell{i, j})
+			}
+		}
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,125 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		puzzle   string
+		solution string
+	}{
+		{
+			"530070000600195000098000060800060003400803001700020006060000280000419005000080079",
+			"534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+		},
+		{
+			"800000000003600000070090200050007000000045700000100030001000068008500010090000400",
+			"812753649943682175675491283154237896369845721287169534521974368438526917796318452",
+		},
+	}
+
+	for _, test := range tests {
+		sudoku, _ := Parse(test.puzzle)
+
+		// Wrong entries of the player must not matter.
+		sudoku.SetValue(0, 1, 9)
+
+		solved, err := sudoku.Solve()
+		if err != nil {
+			t.Fatalf("Sudoku: Can't solve %s: %v", test.puzzle, err)
+		}
+
+		if gridString(solved.values) != test.solution {
+			t.Errorf("Sudoku: Solution of %s should be %s but is %s", test.puzzle, test.solution, gridString(solved.values))
+		}
+
+		if !solved.IsComplete() || solved.initialValues != sudoku.initialValues {
+			t.Errorf("Sudoku: Solution is not a completion of the puzzle:\n%v", solved.ToString())
+		}
+
+		if count, _ := sudoku.countSolutions(2); count != 1 {
+			t.Errorf("Sudoku: %s should have a unique solution but has %d", test.puzzle, count)
+		}
+	}
+
+	// Repeated givens have no solution.
+	sudoku, _ := Parse("550070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	if _, err := sudoku.Solve(); err == nil {
+		t.Errorf("Sudoku: Solves puzzle with repeated givens.")
+	}
+
+	// The empty sudoku has many solutions.
+	var empty Sudoku
+	if count, _ := empty.countSolutions(5); count != 5 {
+		t.Errorf("Sudoku: Empty sudoku should have at least 5 solutions but has %d", count)
+	}
+}
+
+func TestSolveConstraints(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	// The solution has 4 and 6 on (0, 2) and (0, 3), so a cage of 10 is
+	// satisfiable but a cage of 11 is not.
+	sudoku.AddConstraint(Constraint{Cage, []Cell{{0, 2}, {0, 3}}, 10})
+
+	if solved, err := sudoku.Solve(); err != nil || !solved.IsComplete() {
+		t.Errorf("Sudoku: Can't solve with a satisfiable cage: %v", err)
+	}
+
+	sudoku.AddConstraint(Constraint{Cage, []Cell{{0, 2}, {0, 3}}, 11})
+
+	if _, err := sudoku.Solve(); err == nil {
+		t.Errorf("Sudoku: Solves with an unsatisfiable cage.")
+	}
+}
+
+func TestMistakes(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	// The solution has a 4 on (0, 2) and a 6 on (0, 3). A 2 on (0, 3) doesn't
+	// repeat any value yet but is still a mistake.
+	sudoku.SetValue(0, 2, 4)
+	sudoku.SetValue(0, 3, 2)
+
+	mistakes, err := sudoku.Mistakes()
+	if err != nil || len(mistakes) != 1 || mistakes[0] != (Cell{0, 3}) {
+		t.Errorf("Sudoku: Mistakes should be [(0, 3)] but are %v (%v)", mistakes, err)
+	}
+
+	if len(sudoku.Conflicts()) != 0 {
+		t.Errorf("Sudoku: The mistake shouldn't be a conflict yet.")
+	}
+
+	// Changing the initial values of a copy leaves the solution of the
+	// original.
+	changed := sudoku
+	changed.SetInitialValue(0, 2, 1)
+
+	if sudoku.solution == nil || changed.solution != nil {
+		t.Errorf("Sudoku: A copy with other initial values kept the solution.")
+	}
+
+	// The cached solution changes with the initial values.
+	sudoku.SetInitialValue(0, 2, 1)
+
+	if _, err := sudoku.Mistakes(); err == nil {
+		t.Errorf("Sudoku: Finds mistakes of an unsolvable puzzle.")
+	}
+
+	// Puzzles without a unique solution have no mistakes.
+	var empty Sudoku
+	if _, err := empty.Mistakes(); err == nil {
+		t.Errorf("Sudoku: Finds mistakes of a puzzle with many solutions.")
+	}
+
+	// The failure is cached too, until the initial values change.
+	if empty.solutionError == nil {
+		t.Errorf("Sudoku: The missing unique solution was not cached.")
+	}
+
+	empty.SetInitialValue(0, 0, 1)
+	if empty.solutionError != nil {
+		t.Errorf("Sudoku: The cached failure was kept after changing the initial values.")
+	}
+}
@@ -0,0 +1,351 @@
+package sudoku
+
+import (
+	"encoding/json" // File format.
+	"errors"        // Error handling.
+	"fmt"           // Error formatting.
+	"os"            // Files.
+	"sort"          // Sorting days.
+	"time"          // Solve times.
+)
+
+// The version of the statistics file written by @Stats.Save.
+const statsVersion = 1
+
+// SolveRecord is a completed game of a player.
+type SolveRecord struct {
+	// The time the game was completed.
+	Completed time.Time `json:"completed"`
+
+	// The difficulty of the puzzle, see @Rate.
+	Difficulty string `json:"difficulty"`
+
+	// The time played in milliseconds, see @Game.Elapsed.
+	Time int64 `json:"time"`
+
+	Hints    int `json:"hints,omitempty"`
+	Mistakes int `json:"mistakes,omitempty"`
+	Score    int `json:"score"`
+}
+
+// Returns the time played on the game.
+func (record SolveRecord) Duration() time.Duration {
+	return time.Duration(record.Time) * time.Millisecond
+}
+
+// Achievement is a goal a player unlocks once.
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// The achievements, and whether the stats unlock them right after the last
+// record was added.
+var achievements = []struct {
+	Achievement
+	unlocked func(stats *Stats, last SolveRecord) bool
+}{
+	{
+		Achievement{"first-solve", "First Steps", "Solve a puzzle."},
+		func(stats *Stats, last SolveRecord) bool { return true },
+	},
+	{
+		Achievement{"expert-no-hints", "On Your Own", "Solve an Expert puzzle with no hints."},
+		func(stats *Stats, last SolveRecord) bool { return last.Difficulty == Expert && last.Hints == 0 },
+	},
+	{
+		Achievement{"under-3-minutes", "Speed Solver", "Solve a puzzle in under 3 minutes."},
+		func(stats *Stats, last SolveRecord) bool { return last.Duration() < 3*time.Minute },
+	},
+	{
+		Achievement{"flawless-hard", "Flawless", "Solve a Hard or Expert puzzle with no hints and no mistakes."},
+		func(stats *Stats, last SolveRecord) bool {
+			return difficultyIndex(last.Difficulty) >= difficultyIndex(Hard) && last.Hints == 0 && last.Mistakes == 0
+		},
+	},
+	{
+		Achievement{"streak-7", "On a Roll", "Solve puzzles on 7 days in a row."},
+		func(stats *Stats, last SolveRecord) bool { return stats.CurrentStreak(last.Completed) >= 7 },
+	},
+	{
+		Achievement{"hundred", "Centurion", "Solve 100 puzzles."},
+		func(stats *Stats, last SolveRecord) bool { return len(stats.records) >= 100 },
+	},
+}
+
+// Returns every achievement, unlocked or not.
+func Achievements() []Achievement {
+	result := make([]Achievement, len(achievements))
+
+	for k, achievement := range achievements {
+		result[k] = achievement.Achievement
+	}
+
+	return result
+}
+
+// Stats is the history of the games completed by a player, kept on a file.
+type Stats struct {
+	path     string
+	records  []SolveRecord
+	unlocked map[string]time.Time
+
+	// The error of the last record added by @Track.
+	trackError error
+}
+
+// The statistics file, as written by @Stats.Save.
+type jsonStats struct {
+	Version      int                  `json:"version"`
+	Records      []SolveRecord        `json:"records"`
+	Achievements map[string]time.Time `json:"achievements,omitempty"`
+}
+
+// Returns the statistics kept on the file on path, which are empty if the file
+// doesn't exist yet.
+func OpenStats(path string) (*Stats, error) {
+	stats := &Stats{path: path, unlocked: map[string]time.Time{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var doc jsonStats
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Version > statsVersion {
+		return nil, fmt.Errorf("Sudoku: Unsupported stats version %d.", doc.Version)
+	}
+
+	stats.records = doc.Records
+
+	for id, date := range doc.Achievements {
+		stats.unlocked[id] = date
+	}
+
+	return stats, nil
+}
+
+// Writes the statistics to their file, replacing it atomically.
+func (stats *Stats) Save() error {
+	data, err := json.Marshal(jsonStats{statsVersion, stats.records, stats.unlocked})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(stats.path, data)
+}
+
+// Adds the complete game to the history, unlocks the achievements it earns and
+// saves the statistics. Returns the achievements unlocked by the game. An
+// error is returned if the game is not complete or the file can't be written.
+func (stats *Stats) Record(game *Game) ([]Achievement, error) {
+	if !game.IsComplete() {
+		return nil, errors.New("Sudoku: The game is not complete.")
+	}
+
+	record := SolveRecord{
+		Completed: game.CompletedAt(),
+		Time:      game.Elapsed().Milliseconds(),
+		Hints:     game.GetHints(),
+		Mistakes:  game.GetMistakes(),
+	}
+
+	// Puzzles which can't be rated keep the difficulty of their metadata.
+	if rating, err := game.sudoku.Rate(); err == nil {
+		record.Difficulty = rating.Difficulty
+	} else {
+		record.Difficulty = game.sudoku.metadata.Difficulty
+	}
+
+	record.Score, _ = game.Score()
+	stats.records = append(stats.records, record)
+
+	var unlocked []Achievement
+
+	for _, achievement := range achievements {
+		if _, ok := stats.unlocked[achievement.ID]; !ok && achievement.unlocked(stats, record) {
+			stats.unlocked[achievement.ID] = record.Completed
+			unlocked = append(unlocked, achievement.Achievement)
+		}
+	}
+
+	return unlocked, stats.Save()
+}
+
+// Records the game once it's completed, see @OnComplete. The error of the
+// record is returned by @TrackError.
+func (stats *Stats) Track(game *Game) {
+	game.OnComplete(func(game *Game) {
+		_, stats.trackError = stats.Record(game)
+	})
+}
+
+// Returns the error of the last game recorded by @Track, nil if it succeeded.
+func (stats *Stats) TrackError() error {
+	return stats.trackError
+}
+
+// Returns the completed games of the difficulty, or all of them if the
+// difficulty is empty, the oldest first.
+func (stats *Stats) Records(difficulty string) []SolveRecord {
+	var records []SolveRecord
+
+	for _, record := range stats.records {
+		if difficulty == "" || record.Difficulty == difficulty {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// StatsSummary sums up the completed games of a difficulty.
+type StatsSummary struct {
+	Solves int
+
+	// The best and the average time, zero if there are no games.
+	BestTime    time.Duration
+	AverageTime time.Duration
+
+	BestScore int
+	Hints     int
+	Mistakes  int
+}
+
+// Returns the summary of the games of the difficulty, or of all of them if the
+// difficulty is empty.
+func (stats *Stats) Summary(difficulty string) StatsSummary {
+	var summary StatsSummary
+	var total time.Duration
+
+	for _, record := range stats.Records(difficulty) {
+		if summary.Solves == 0 || record.Duration() < summary.BestTime {
+			summary.BestTime = record.Duration()
+		}
+
+		if record.Score > summary.BestScore {
+			summary.BestScore = record.Score
+		}
+
+		summary.Solves++
+		summary.Hints += record.Hints
+		summary.Mistakes += record.Mistakes
+		total += record.Duration()
+	}
+
+	if summary.Solves > 0 {
+		summary.AverageTime = total / time.Duration(summary.Solves)
+	}
+
+	return summary
+}
+
+// Returns the days with at least one completed game, in the location of the
+// completion times, without repetitions and in order.
+func (stats *Stats) solveDays() []time.Time {
+	seen := map[time.Time]bool{}
+	var days []time.Time
+
+	for _, record := range stats.records {
+		if day := dailyDay(record.Completed); !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+
+	return days
+}
+
+// Returns the number of days in a row with a completed game, ending on the
+// day of the date or the day before, so the streak isn't lost until the day
+// ends.
+func (stats *Stats) CurrentStreak(date time.Time) int {
+	days := stats.solveDays()
+	day := dailyDay(date)
+	streak := 0
+
+	for k := len(days) - 1; k >= 0; k-- {
+		if days[k].After(day) {
+			continue
+		}
+
+		if streak == 0 && days[k].Before(day.AddDate(0, 0, -1)) {
+			return 0
+		}
+
+		if streak > 0 && !days[k].Equal(day) {
+			break
+		}
+
+		streak++
+		day = days[k].AddDate(0, 0, -1)
+	}
+
+	return streak
+}
+
+// Returns the longest number of days in a row with a completed game.
+func (stats *Stats) LongestStreak() int {
+	longest, streak := 0, 0
+	var previous time.Time
+
+	for _, day := range stats.solveDays() {
+		if streak > 0 && day.Equal(previous.AddDate(0, 0, 1)) {
+			streak++
+		} else {
+			streak = 1
+		}
+
+		if streak > longest {
+			longest = streak
+		}
+
+		previous = day
+	}
+
+	return longest
+}
+
+// Returns the ids of the achievements unlocked so far, see @Achievements, with
+// the time they were unlocked.
+func (stats *Stats) Unlocked() map[string]time.Time {
+	result := map[string]time.Time{}
+
+	for id, date := range stats.unlocked {
+		result[id] = date
+	}
+
+	return result
+}
+
+// StatsStore keeps the statistics of every player as files of a directory.
+type StatsStore struct {
+	Dir string
+}
+
+// Returns the statistics of the player, see @OpenStats, creating the directory
+// if needed.
+func (store StatsStore) Open(player string) (*Stats, error) {
+	path, err := storeFile(store.Dir, player)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(store.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return OpenStats(path)
+}
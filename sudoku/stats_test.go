@@ -0,0 +1,126 @@
+package sudoku
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatsTrack(t *testing.T) {
+	store := StatsStore{filepath.Join(t.TempDir(), "stats")}
+
+	stats, err := store.Open("alice")
+	if err != nil {
+		t.Fatalf("Sudoku: Opening stats failed: %v", err)
+	}
+
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	clock := &fakeClock{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	game := NewGame(sudoku)
+	game.SetClock(clock)
+	stats.Track(game)
+
+	game.NextHint()
+	clock.Advance(2 * time.Minute)
+	solveGame(game)
+
+	if stats.TrackError() != nil {
+		t.Fatalf("Sudoku: Recording the game failed: %v", stats.TrackError())
+	}
+
+	records := stats.Records("")
+	if len(records) != 1 || records[0].Duration() != 2*time.Minute || records[0].Hints != 1 || records[0].Difficulty != Easy {
+		t.Fatalf("Sudoku: Completed game should be recorded but records are %+v", records)
+	}
+
+	unlocked := stats.Unlocked()
+	if _, ok := unlocked["first-solve"]; !ok {
+		t.Errorf("Sudoku: First solve should be unlocked")
+	}
+
+	if _, ok := unlocked["under-3-minutes"]; !ok {
+		t.Errorf("Sudoku: Solve under 3 minutes should be unlocked")
+	}
+
+	if _, ok := unlocked["flawless-hard"]; ok {
+		t.Errorf("Sudoku: Flawless should not be unlocked by an Easy puzzle")
+	}
+
+	// The history survives reopening the store.
+	reopened, err := store.Open("alice")
+	if err != nil {
+		t.Fatalf("Sudoku: Reopening stats failed: %v", err)
+	}
+
+	if got := reopened.Summary(Easy); got.Solves != 1 || got.BestTime != 2*time.Minute || got.Hints != 1 {
+		t.Errorf("Sudoku: Reopened summary should have the solve but is %+v", got)
+	}
+
+	if len(reopened.Unlocked()) != len(unlocked) {
+		t.Errorf("Sudoku: Reopened achievements should be %v but are %v", unlocked, reopened.Unlocked())
+	}
+
+	if _, err := store.Open("../alice"); err == nil {
+		t.Errorf("Sudoku: Invalid player name should be rejected")
+	}
+}
+
+func TestStatsRecordIncomplete(t *testing.T) {
+	stats, _ := OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	if _, err := stats.Record(NewGame(sudoku)); err == nil {
+		t.Errorf("Sudoku: Recording an incomplete game should fail")
+	}
+}
+
+func TestStatsSummary(t *testing.T) {
+	stats := &Stats{records: []SolveRecord{
+		{Difficulty: Easy, Time: 60000, Score: 900, Hints: 2},
+		{Difficulty: Easy, Time: 180000, Score: 700, Mistakes: 1},
+		{Difficulty: Hard, Time: 600000, Score: 2500},
+	}}
+
+	want := StatsSummary{Solves: 2, BestTime: time.Minute, AverageTime: 2 * time.Minute, BestScore: 900, Hints: 2, Mistakes: 1}
+	if got := stats.Summary(Easy); got != want {
+		t.Errorf("Sudoku: Easy summary should be %+v but is %+v", want, got)
+	}
+
+	if got := stats.Summary(""); got.Solves != 3 || got.BestScore != 2500 {
+		t.Errorf("Sudoku: Summary of all games should have 3 solves but is %+v", got)
+	}
+
+	if got := stats.Summary(Expert); got != (StatsSummary{}) {
+		t.Errorf("Sudoku: Expert summary should be empty but is %+v", got)
+	}
+}
+
+func TestStatsStreaks(t *testing.T) {
+	day := func(d, hour int) SolveRecord {
+		return SolveRecord{Completed: time.Date(2024, 3, d, hour, 0, 0, 0, time.UTC)}
+	}
+
+	stats := &Stats{records: []SolveRecord{day(1, 9), day(2, 9), day(3, 9), day(3, 22), day(4, 9), day(7, 9), day(8, 9)}}
+
+	if got := stats.LongestStreak(); got != 4 {
+		t.Errorf("Sudoku: Longest streak should be 4 but is %d", got)
+	}
+
+	tests := []struct {
+		date time.Time
+		want int
+	}{
+		{time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC), 2},
+		{time.Date(2024, 3, 9, 8, 0, 0, 0, time.UTC), 2},
+		{time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC), 0},
+		{time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC), 4},
+		{time.Date(2024, 3, 6, 8, 0, 0, 0, time.UTC), 0},
+	}
+
+	for _, test := range tests {
+		if got := stats.CurrentStreak(test.date); got != test.want {
+			t.Errorf("Sudoku: Streak on %v should be %d but is %d", test.date, test.want, got)
+		}
+	}
+}
@@ -1,8 +1,7 @@
-package main
+package sudoku
 
 import (
-	"errors"  // Error handling.
-	"strconv" // String Conversions. (Integer to String)
+	"errors" // Error handling.
 )
 
 type Sudoku struct {
@@ -11,6 +10,48 @@ type Sudoku struct {
 
 	// The initial sudoku values; you can't modify this ones while playing.
 	initialValues [9][9]int
+
+	// The pencil marks of each cell, the bit v is set if v is a candidate.
+	candidates [9][9]uint16
+
+	// The variant constraints of the puzzle, see @Constraint.
+	constraints []Constraint
+
+	// Information about the puzzle, like its author or difficulty.
+	metadata Metadata
+
+	// The unique solution of the puzzle once computed, or the reason it has
+	// none, see @uniqueSolution. Methods which only read the puzzle, like
+	// @Mistakes, fill it in, so they change the sudoku and are not safe to
+	// call on it from several goroutines. Copies share the solution, which is
+	// never written once computed; every method changing the initial values or
+	// the constraints clears it with @forgetSolution.
+	solution      *[9][9]int
+	solutionError error
+}
+
+// Metadata holds descriptive information about a puzzle. None of the fields are
+// required.
+type Metadata struct {
+	Title       string `json:"title,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Source      string `json:"source,omitempty"`
+	SourceURL   string `json:"sourceURL,omitempty"`
+	Difficulty  string `json:"difficulty,omitempty"`
+	Rules       string `json:"rules,omitempty"`
+}
+
+// Returns the metadata of the sudoku.
+func (sudoku *Sudoku) GetMetadata() Metadata {
+	return sudoku.metadata
+}
+
+// Replaces the metadata of the sudoku.
+func (sudoku *Sudoku) SetMetadata(metadata Metadata) {
+	sudoku.metadata = metadata
 }
 
 // Set an initial value for the sudoku in the cell on the row x and column
@@ -28,6 +69,7 @@ func (sudoku *Sudoku) SetInitialValue(x, y, val int) error {
 	if val >= 1 && val <= 9 {
 		sudoku.values[x][y] = val
 		sudoku.initialValues[x][y] = val
+		sudoku.forgetSolution()
 	} else {
 		return errors.New("Sudoku: Not a valid entry.")
 	}
@@ -59,6 +101,63 @@ func (sudoku *Sudoku) SetValue(x, y, val int) error {
 	return nil
 }
 
+// Erases the value of the sudoku in the cell on the row x and column y,
+// leaving it empty. Initial values can't be erased.
+func (sudoku *Sudoku) ClearValue(x, y int) error {
+	if x < 0 || x > 8 {
+		return errors.New("Sudoku: Invalid row.")
+	}
+
+	if y < 0 || y > 8 {
+		return errors.New("Sudoku: Invalid column.")
+	}
+
+	if sudoku.initialValues[x][y] != 0 {
+		return errors.New("Sudoku: Can't overwrite initial value.")
+	}
+
+	sudoku.values[x][y] = 0
+
+	return nil
+}
+
+// Erases every value and candidate, leaving only the initial values.
+func (sudoku *Sudoku) Reset() {
+	sudoku.values = sudoku.initialValues
+	sudoku.candidates = [9][9]uint16{}
+}
+
+// Returns the empty cells of the sudoku, from left to right and from top to
+// bottom.
+func (sudoku *Sudoku) EmptyCells() []Cell {
+	var cells []Cell
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if sudoku.values[i][j] == 0 {
+				cells = append(cells, Cell{i, j})
+			}
+		}
+	}
+
+	return cells
+}
+
+// Returns the number of filled cells, counting the initial values.
+func (sudoku *Sudoku) FilledCount() int {
+	count := 0
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if sudoku.values[i][j] != 0 {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
 // Returns the value of the sudoku on the row x and column y.
 func (sudoku *Sudoku) GetValue(x, y int) (int, error) {
 	if x < 0 || x > 8 {
@@ -72,6 +171,87 @@ func (sudoku *Sudoku) GetValue(x, y int) (int, error) {
 	return sudoku.values[x][y], nil
 }
 
+// Adds val to the candidates of the cell on the row x and column y, or removes
+// it if it was already a candidate. Initial values can't have candidates.
+func (sudoku *Sudoku) ToggleCandidate(x, y, val int) error {
+	if x < 0 || x > 8 {
+		return errors.New("Sudoku: Invalid row.")
+	}
+
+	if y < 0 || y > 8 {
+		return errors.New("Sudoku: Invalid column.")
+	}
+
+	if sudoku.initialValues[x][y] != 0 {
+		return errors.New("Sudoku: Can't overwrite initial value.")
+	}
+
+	if val < 1 || val > 9 {
+		return errors.New("Sudoku: Not a valid entry.")
+	}
+
+	sudoku.candidates[x][y] ^= 1 << val
+
+	return nil
+}
+
+// Replaces the candidates of the cell on the row x and column y. An empty list
+// removes all of them.
+func (sudoku *Sudoku) SetCandidates(x, y int, vals []int) error {
+	var mask uint16
+
+	if x < 0 || x > 8 {
+		return errors.New("Sudoku: Invalid row.")
+	}
+
+	if y < 0 || y > 8 {
+		return errors.New("Sudoku: Invalid column.")
+	}
+
+	if sudoku.initialValues[x][y] != 0 && len(vals) > 0 {
+		return errors.New("Sudoku: Can't overwrite initial value.")
+	}
+
+	for _, val := range vals {
+		if val < 1 || val > 9 {
+			return errors.New("Sudoku: Not a valid entry.")
+		}
+
+		mask |= 1 << val
+	}
+
+	sudoku.candidates[x][y] = mask
+
+	return nil
+}
+
+// Returns the candidates of the cell on the row x and column y in increasing
+// order.
+func (sudoku *Sudoku) GetCandidates(x, y int) ([]int, error) {
+	if x < 0 || x > 8 {
+		return nil, errors.New("Sudoku: Invalid row.")
+	}
+
+	if y < 0 || y > 8 {
+		return nil, errors.New("Sudoku: Invalid column.")
+	}
+
+	return maskDigits(sudoku.candidates[x][y]), nil
+}
+
+// Returns the digits whose bit is set on the mask in increasing order.
+func maskDigits(mask uint16) []int {
+	var digits []int
+
+	for val := 1; val <= 9; val++ {
+		if mask&(1<<val) != 0 {
+			digits = append(digits, val)
+		}
+	}
+
+	return digits
+}
+
 // Returns the row x of the sudoku as an array of size 9.
 func (sudoku *Sudoku) GetRow(x int) [9]int {
 	var row [9]int
@@ -219,112 +399,41 @@ func (sudoku *Sudoku) IsValidBlock(z int) bool {
 }
 
 // A completed sudoku is a Sudoku where all its rows, columns, and blocks are
-// valid.
+// valid, and none of its variant constraints is violated.
 func (sudoku *Sudoku) IsComplete() bool {
 	for i := 0; i < 9; i++ {
 		if !sudoku.IsValidRow(i) ||
-			!sudoku.IsValidBlock(i) ||
+			!sudoku.IsValidColumn(i) ||
 			!sudoku.IsValidBlock(i) {
 			return false
 		}
 	}
 
-	return true
-}
-
-// Prints a part of a grid with (length) squares in a row.
-// Part 1: Upper Grid.
-// Part 2: Middle Grid.
-// Part 3: Bottom Grid.
-func printGridPart(part, length int, delim bool) string {
-	var gridString string
-
-	switch part {
-	case 1:
-		gridString = "╔"
-	case 2:
-		if delim {
-			gridString = "╠"
-		} else {
-			gridString = "├"
-		}
-	case 3:
-		gridString = "╚"
-	}
-
-	for i := 0; i < length; i++ {
-		gridString += "───"
-
-		if i < (length - 1) {
-			if delim && (i+1)%3 == 0 {
-				switch part {
-				case 1:
-					gridString += "╦"
-				case 2:
-					gridString += "╬"
-				case 3:
-					gridString += "╩"
-				}
-			} else {
-				switch part {
-				case 1:
-					gridString += "┬"
-				case 2:
-					gridString += "┼"
-				case 3:
-					gridString += "┴"
-				}
-			}
-		} else {
-			switch part {
-			case 1:
-				gridString += "╗"
-			case 2:
-				if delim {
-					gridString += "╣"
-				} else {
-					gridString += "┤"
-				}
-			case 3:
-				gridString += "╝"
-			}
+	for i := range sudoku.constraints {
+		if sudoku.constraints[i].Violated(sudoku) {
+			return false
 		}
 	}
 
-	return gridString
+	return true
 }
 
-// Returns the given sudoku in String format.
-func (sudoku *Sudoku) ToString() string {
-	var delim bool
-	sudokuString := printGridPart(1, 9, true) + "\n"
-
-	for i, row := range sudoku.values {
-
-		// Print the current row values.
-		for j := 0; j < len(row); j++ {
-
-			if (i+1)%3 == 0 {
-				delim = true
-			} else {
-				delim = false
-			}
-
-			sudokuString += "│ "
-			sudokuString += strconv.Itoa(row[j]) + " "
+// Returns the values which can still be written on the row x and column y
+// without repeating a value of its row, column or block, as a bitmask where
+// the bit v is set if v is possible.
+func (sudoku *Sudoku) possibleMask(x, y int) uint16 {
+	var used uint16
 
-			if j == len(row)-1 {
-				sudokuString += "│\n"
-			}
-		}
-
-		// Depending on the row, print the corresponding grid part.
-		if i < 8 {
-			sudokuString += printGridPart(2, 9, delim) + "\n"
-		} else {
-			sudokuString += printGridPart(3, 9, delim) + "\n"
-		}
+	for k := 0; k < 9; k++ {
+		used |= 1 << sudoku.values[x][k]
+		used |= 1 << sudoku.values[k][y]
+		used |= 1 << sudoku.values[(x/3)*3+k/3][(y/3)*3+k%3]
 	}
 
-	return sudokuString
+	return ^used & 0x3FE
+}
+
+// Returns the given sudoku in String format, see @Renderer for other formats.
+func (sudoku *Sudoku) ToString() string {
+	return Renderer{}.Render(sudoku)
 }
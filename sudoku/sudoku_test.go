@@ -1,4 +1,4 @@
-package main
+package sudoku
 
 import (
 	"testing"
@@ -199,6 +199,99 @@ func TestGetValue(t *testing.T) {
 	}
 }
 
+func TestClearValue(t *testing.T) {
+	var sudoku Sudoku
+
+	sudoku.SetInitialValue(0, 0, 5)
+	sudoku.SetValue(0, 1, 3)
+
+	// Must erase a value which is not an initial value.
+	if err := sudoku.ClearValue(0, 1); err != nil {
+		t.Errorf("Sudoku: Can't erase value: %v", err)
+	}
+
+	if val, _ := sudoku.GetValue(0, 1); val != 0 {
+		t.Errorf("Sudoku: Value should be 0 but is %d", val)
+	}
+
+	// Must return an error when erasing an initial value.
+	if err := sudoku.ClearValue(0, 0); err == nil {
+		t.Errorf("Sudoku: Can erase initial value.")
+	}
+
+	// Must return an error for invalid rows and columns.
+	for _, val := range [4]int{-1, -2, 9, 10} {
+		if sudoku.ClearValue(val, 0) == nil || sudoku.ClearValue(0, val) == nil {
+			t.Errorf("Sudoku: Can erase invalid cell on %d", val)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	var sudoku Sudoku
+
+	sudoku.SetInitialValue(0, 0, 5)
+	sudoku.SetValue(0, 1, 3)
+	sudoku.SetValue(8, 8, 1)
+	sudoku.ToggleCandidate(4, 4, 2)
+
+	if sudoku.FilledCount() != 3 || len(sudoku.EmptyCells()) != 78 {
+		t.Errorf("Sudoku: Should have 3 filled cells but has %d", sudoku.FilledCount())
+	}
+
+	sudoku.Reset()
+
+	if sudoku.FilledCount() != 1 || len(sudoku.EmptyCells()) != 80 {
+		t.Errorf("Sudoku: Should have 1 filled cell after reset but has %d", sudoku.FilledCount())
+	}
+
+	if val, _ := sudoku.GetValue(0, 0); val != 5 {
+		t.Errorf("Sudoku: Initial value was erased by reset.")
+	}
+
+	if candidates, _ := sudoku.GetCandidates(4, 4); len(candidates) != 0 {
+		t.Errorf("Sudoku: Candidates were not erased by reset.")
+	}
+
+	if cells := sudoku.EmptyCells(); cells[0] != (Cell{0, 1}) || cells[79] != (Cell{8, 8}) {
+		t.Errorf("Sudoku: Empty cells are not in order: %v", cells)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	var sudoku Sudoku
+
+	// Toggling twice must remove the candidate again.
+	sudoku.ToggleCandidate(0, 0, 3)
+	sudoku.ToggleCandidate(0, 0, 5)
+	sudoku.ToggleCandidate(0, 0, 3)
+
+	if vals, _ := sudoku.GetCandidates(0, 0); len(vals) != 1 || vals[0] != 5 {
+		t.Errorf("Sudoku: Candidates should be [5] but are %v", vals)
+	}
+
+	// Candidates are replaced and returned in order.
+	sudoku.SetCandidates(0, 0, []int{9, 1, 4})
+
+	if vals, _ := sudoku.GetCandidates(0, 0); len(vals) != 3 || vals[0] != 1 || vals[1] != 4 || vals[2] != 9 {
+		t.Errorf("Sudoku: Candidates should be [1 4 9] but are %v", vals)
+	}
+
+	// Must return an error for invalid candidates.
+	for _, val := range [4]int{0, -1, 10, 100} {
+		if err := sudoku.ToggleCandidate(0, 0, val); err == nil {
+			t.Errorf("Sudoku: Accepts invalid candidate %d", val)
+		}
+	}
+
+	// Must return an error for initial values.
+	sudoku.SetInitialValue(8, 8, 1)
+
+	if err := sudoku.ToggleCandidate(8, 8, 2); err == nil {
+		t.Errorf("Sudoku: Accepts candidates on initial values.")
+	}
+}
+
 func TestGetRow(t *testing.T) {
 	var sudoku Sudoku
 
@@ -616,3 +709,18 @@ func TestIsComplete(t *testing.T) {
 		t.Errorf("Sudoku: The following Sudoku returns false when asked if complete: \n%v", sudoku.ToString())
 	}
 }
+
+func TestIsCompleteColumns(t *testing.T) {
+	// Every row and block is valid, but each band repeats the first one, so
+	// every column has duplicates.
+	band := "123456789" + "456789123" + "789123456"
+
+	sudoku, err := Parse(band + band + band)
+	if err != nil {
+		t.Fatalf("Sudoku: Parsing failed: %v", err)
+	}
+
+	if sudoku.IsComplete() {
+		t.Errorf("Sudoku: The following sudoku is considered to be complete when its columns repeat values %v", sudoku.ToString())
+	}
+}
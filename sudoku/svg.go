@@ -0,0 +1,159 @@
+package sudoku
+
+import (
+	"fmt"     // String formatting.
+	"io"      // Writers.
+	"strconv" // String Conversions.
+	"strings" // String manipulation.
+)
+
+// How the candidates of a cell are drawn.
+const (
+	// Each candidate in its own position of a 3x3 grid inside the cell, 1 on
+	// the top left corner and 9 on the bottom right one.
+	CornerMarks = iota
+
+	// All candidates in a line on the centre of the cell.
+	CentreMarks
+)
+
+// SVGOptions configures @RenderSVG. The zero value draws 50 pixel cells with
+// corner pencil marks and no highlights.
+type SVGOptions struct {
+	// The size of a cell in pixels, 50 if zero.
+	CellSize int
+
+	// CornerMarks or CentreMarks.
+	PencilMarks int
+
+	// The colour of the values entered by the player, the initial values are
+	// always black. "#1a5fb4" if empty.
+	EntryColor string
+
+	// The background colour of some cells, like "yellow" or "#ffe0e0".
+	Highlights map[Cell]string
+}
+
+// RenderSVG draws the sudoku as an SVG image: the grid with thick borders
+// between blocks, initial values in bold, entered values in colour and the
+// candidates of the empty cells as small pencil marks. The same sudoku and
+// options always produce the same output.
+func (sudoku *Sudoku) RenderSVG(w io.Writer, opts SVGOptions) error {
+	var b strings.Builder
+
+	svgStart(&b, opts)
+	sudoku.writeSVG(&b, opts)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Returns the size of a cell and the margin around the grid of the images
+// drawn with the options.
+func svgSizes(opts SVGOptions) (int, int) {
+	cell := opts.CellSize
+	if cell <= 0 {
+		cell = 50
+	}
+
+	return cell, 2
+}
+
+// Writes the opening svg element of the images drawn with the options.
+func svgStart(b *strings.Builder, opts SVGOptions) {
+	cell, margin := svgSizes(opts)
+	size := 9*cell + 2*margin
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		size, size, size, size)
+}
+
+// Writes the elements of the image drawn by @RenderSVG, without the svg
+// element around them.
+func (sudoku *Sudoku) writeSVG(b *strings.Builder, opts SVGOptions) {
+	cell, margin := svgSizes(opts)
+	size := 9*cell + 2*margin
+
+	entryColor := opts.EntryColor
+	if entryColor == "" {
+		entryColor = "#1a5fb4"
+	}
+
+	fmt.Fprintf(b, `<rect x="0" y="0" width="%d" height="%d" fill="white"/>`+"\n", size, size)
+
+	// Highlights go first so the grid is drawn over them.
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if color, ok := opts.Highlights[Cell{i, j}]; ok {
+				fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+					margin+j*cell, margin+i*cell, cell, cell, escapeXML(color))
+			}
+		}
+	}
+
+	// Thin lines between cells and thick lines between blocks.
+	for k := 0; k <= 9; k++ {
+		width := 1
+		if k%3 == 0 {
+			width = 3
+		}
+
+		pos := margin + k*cell
+
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+			margin, pos, size-margin, pos, width)
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+			pos, margin, pos, size-margin, width)
+	}
+
+	font := `font-family="sans-serif" text-anchor="middle" dominant-baseline="central"`
+
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			x := margin + j*cell
+			y := margin + i*cell
+
+			if val := sudoku.values[i][j]; val != 0 {
+				if sudoku.initialValues[i][j] != 0 {
+					fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" font-weight="bold" fill="black" %s>%d</text>`+"\n",
+						x+cell/2, y+cell/2, cell*3/5, font, val)
+				} else {
+					fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" fill="%s" %s>%d</text>`+"\n",
+						x+cell/2, y+cell/2, cell*3/5, escapeXML(entryColor), font, val)
+				}
+
+				continue
+			}
+
+			marks := maskDigits(sudoku.candidates[i][j])
+
+			if len(marks) == 0 {
+				continue
+			}
+
+			if opts.PencilMarks == CentreMarks {
+				text := ""
+				for _, mark := range marks {
+					text += strconv.Itoa(mark)
+				}
+
+				fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" fill="%s" %s>%s</text>`+"\n",
+					x+cell/2, y+cell/2, cell/4, escapeXML(entryColor), font, text)
+
+				continue
+			}
+
+			for _, mark := range marks {
+				fmt.Fprintf(b, `<text x="%d" y="%d" font-size="%d" fill="%s" %s>%d</text>`+"\n",
+					x+cell/6+((mark-1)%3)*cell/3, y+cell/6+((mark-1)/3)*cell/3, cell/4,
+					escapeXML(entryColor), font, mark)
+			}
+		}
+	}
+}
+
+// Returns the string with the special characters of XML escaped.
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
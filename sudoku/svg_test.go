@@ -0,0 +1,69 @@
+package sudoku
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "Update the golden files in testdata.")
+
+// Compares the output with the golden file in testdata, or replaces the file
+// when running with -update.
+func checkGolden(t *testing.T, name string, output []byte) {
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		os.MkdirAll("testdata", 0755)
+
+		if err := os.WriteFile(path, output, 0644); err != nil {
+			t.Fatalf("Sudoku: Can't update golden file: %v", err)
+		}
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Sudoku: Can't read golden file: %v", err)
+	}
+
+	if !bytes.Equal(output, golden) {
+		t.Errorf("Sudoku: Output differs from %s, run the tests with -update if this is expected.", path)
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	var b1, b2 bytes.Buffer
+
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	sudoku.SetValue(0, 2, 4)
+	sudoku.SetCandidates(0, 3, []int{2, 6})
+	sudoku.SetCandidates(0, 5, []int{2, 8})
+
+	opts := SVGOptions{
+		Highlights: map[Cell]string{{0, 2}: "#ffe0e0", {4, 4}: "yellow"},
+	}
+
+	if err := sudoku.RenderSVG(&b1, opts); err != nil {
+		t.Fatalf("Sudoku: Can't render SVG: %v", err)
+	}
+
+	sudoku.RenderSVG(&b2, opts)
+
+	if b1.String() != b2.String() {
+		t.Errorf("Sudoku: SVG output is not deterministic.")
+	}
+
+	checkGolden(t, "render.svg", b1.Bytes())
+
+	// Centre marks are drawn as a single text.
+	b1.Reset()
+	sudoku.RenderSVG(&b1, SVGOptions{PencilMarks: CentreMarks})
+
+	if !strings.Contains(b1.String(), ">26</text>") {
+		t.Errorf("Sudoku: Centre marks were not drawn:\n%s", b1.String())
+	}
+}
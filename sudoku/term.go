@@ -0,0 +1,46 @@
+package sudoku
+
+import (
+	"io"      // Input of the game.
+	"os"      // Terminals.
+	"os/exec" // Running stty.
+	"strings" // String manipulation.
+)
+
+// Runs stty on the terminal with the given arguments.
+func stty(terminal *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = terminal
+
+	out, err := cmd.Output()
+
+	return strings.TrimSpace(string(out)), err
+}
+
+// Switches the terminal behind the input to raw mode, where every key is read
+// as soon as it's typed and not echoed. Returns a function which restores the
+// previous mode. Inputs which are not a terminal, like pipes and files, are
+// read as they are.
+func makeRaw(in io.Reader) (func(), error) {
+	terminal, ok := in.(*os.File)
+	if !ok {
+		return func() {}, nil
+	}
+
+	if info, err := terminal.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return func() {}, nil
+	}
+
+	state, err := stty(terminal, "-g")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stty(terminal, "raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		stty(terminal, state)
+	}, nil
+}
@@ -0,0 +1,167 @@
+package sudoku
+
+import (
+	"errors" // Error handling.
+	"time"   // Elapsed time.
+)
+
+// Clock tells the current time to a game. Games use the system clock unless
+// another one is given, like a fake one in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// The clock of the operating system.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// The scores of a game solved right away without hints or mistakes, for each
+// difficulty, see @Score.
+var baseScores = map[string]int{
+	Easy:   1000,
+	Medium: 2000,
+	Hard:   3000,
+	Expert: 4000,
+}
+
+// The time a player is expected to take on each difficulty. Games solved in
+// less time get a bonus.
+var parTimes = map[string]time.Duration{
+	Easy:   5 * time.Minute,
+	Medium: 10 * time.Minute,
+	Hard:   20 * time.Minute,
+	Expert: 30 * time.Minute,
+}
+
+// The points taken from the score for every hint and every mistake.
+const (
+	hintPenalty    = 100
+	mistakePenalty = 50
+)
+
+// Replaces the clock of the game and restarts the timer from zero, so it's
+// meant to be called right after @NewGame.
+func (game *Game) SetClock(clock Clock) {
+	game.clock = clock
+	game.elapsed = 0
+	game.started = clock.Now()
+}
+
+// Returns the time played, not counting the pauses. The timer stops once the
+// sudoku is complete or the game is lost.
+func (game *Game) Elapsed() time.Duration {
+	if game.started.IsZero() {
+		return game.elapsed
+	}
+
+	return game.elapsed + game.clock.Now().Sub(game.started)
+}
+
+// Stops the timer until @Resume is called. Moves can still be made while the
+// game is paused, it's up to the front-end to hide the grid.
+func (game *Game) Pause() {
+	if game.started.IsZero() {
+		return
+	}
+
+	game.elapsed += game.clock.Now().Sub(game.started)
+	game.started = time.Time{}
+}
+
+// Restarts the timer after @Pause. Complete or lost games can't be resumed.
+func (game *Game) Resume() {
+	if game.started.IsZero() && game.completed.IsZero() && !game.IsLost() {
+		game.started = game.clock.Now()
+	}
+}
+
+// Returns true if the timer is stopped, either by @Pause or because the game is
+// complete or lost.
+func (game *Game) IsPaused() bool {
+	return game.started.IsZero()
+}
+
+// Stops the timer, records the completion time and calls the functions given
+// to @OnComplete the first time the sudoku is complete, see @IsComplete.
+func (game *Game) checkComplete() {
+	if !game.completed.IsZero() || !game.sudoku.IsComplete() {
+		return
+	}
+
+	game.completed = game.clock.Now()
+	game.Pause()
+
+	for _, fn := range game.onComplete {
+		fn(game)
+	}
+}
+
+// Adds a function called once, right after the move which completes the
+// sudoku.
+func (game *Game) OnComplete(fn func(game *Game)) {
+	game.onComplete = append(game.onComplete, fn)
+}
+
+// Returns true if the sudoku was completed. A game stays complete even if
+// values are cleared afterwards.
+func (game *Game) IsComplete() bool {
+	return !game.completed.IsZero()
+}
+
+// Returns the time the sudoku was completed, or the zero time if it wasn't.
+func (game *Game) CompletedAt() time.Time {
+	return game.completed
+}
+
+// Returns the easiest deduction on the current values, see @NextHint, and
+// counts it as a hint taken.
+func (game *Game) NextHint() (Hint, error) {
+	hint, err := game.sudoku.NextHint()
+
+	if err == nil {
+		game.hints++
+		game.logHint(hint)
+	}
+
+	return hint, err
+}
+
+// Returns the number of hints taken so far.
+func (game *Game) GetHints() int {
+	return game.hints
+}
+
+// Returns the score of a complete game. The base score of the difficulty of
+// the puzzle, see @Rate, grows up to twice as much the faster it's solved
+// under the par time of the difficulty, and every hint and mistake takes
+// points away. The score is never negative. An error is returned if the game
+// is not complete or the puzzle can't be rated.
+func (game *Game) Score() (int, error) {
+	if !game.IsComplete() {
+		return 0, errors.New("Sudoku: The game is not complete.")
+	}
+
+	rating, err := game.sudoku.Rate()
+	if err != nil {
+		return 0, err
+	}
+
+	base := baseScores[rating.Difficulty]
+	par := parTimes[rating.Difficulty]
+	score := base
+
+	if elapsed := game.Elapsed(); elapsed < par {
+		score += int(int64(base) * int64(par-elapsed) / int64(par))
+	}
+
+	score -= game.hints*hintPenalty + game.mistakes*mistakePenalty
+
+	if score < 0 {
+		score = 0
+	}
+
+	return score, nil
+}
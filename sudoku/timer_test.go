@@ -0,0 +1,107 @@
+package sudoku
+
+import (
+	"testing"
+	"time"
+)
+
+// A clock which only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.now = clock.now.Add(d)
+}
+
+// Fills every empty cell of the game with its solution.
+func solveGame(game *Game) {
+	solution, _ := game.sudoku.uniqueSolution()
+
+	for _, cell := range game.sudoku.EmptyCells() {
+		game.SetValue(cell.Row, cell.Column, solution[cell.Row][cell.Column])
+	}
+}
+
+func TestTimer(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	clock := &fakeClock{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	game := NewGame(sudoku)
+	game.SetClock(clock)
+
+	clock.Advance(time.Minute)
+	game.Pause()
+	clock.Advance(time.Hour)
+
+	if !game.IsPaused() || game.Elapsed() != time.Minute {
+		t.Errorf("Sudoku: Paused timer should show 1m0s but shows %v", game.Elapsed())
+	}
+
+	game.Resume()
+	clock.Advance(time.Minute)
+
+	if game.Elapsed() != 2*time.Minute {
+		t.Errorf("Sudoku: Timer should show 2m0s but shows %v", game.Elapsed())
+	}
+
+	solveGame(game)
+	clock.Advance(time.Minute)
+	game.Resume()
+
+	if !game.IsComplete() || !game.CompletedAt().Equal(clock.now.Add(-time.Minute)) {
+		t.Errorf("Sudoku: Game should be complete at %v but is at %v", clock.now.Add(-time.Minute), game.CompletedAt())
+	}
+
+	if game.Elapsed() != 2*time.Minute {
+		t.Errorf("Sudoku: Timer should stop on completion but shows %v", game.Elapsed())
+	}
+}
+
+func TestScore(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+
+	tests := []struct {
+		minutes  int
+		hints    int
+		mistakes int
+		score    int
+	}{
+		{0, 0, 0, 2000},
+		{4, 0, 0, 1200},
+		{10, 0, 0, 1000},
+		{10, 2, 1, 750},
+		{10, 10, 10, 0},
+	}
+
+	for _, test := range tests {
+		clock := &fakeClock{time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)}
+		game := NewGame(sudoku)
+		game.SetClock(clock)
+
+		if _, err := game.Score(); err == nil {
+			t.Errorf("Sudoku: Incomplete game has a score.")
+		}
+
+		for k := 0; k < test.hints; k++ {
+			game.NextHint()
+		}
+
+		for k := 0; k < test.mistakes; k++ {
+			// The solution has a 4 on (0, 2).
+			game.SetValue(0, 2, 1)
+			game.ClearValue(0, 2)
+		}
+
+		clock.Advance(time.Duration(test.minutes) * time.Minute)
+		solveGame(game)
+
+		if score, err := game.Score(); err != nil || score != test.score {
+			t.Errorf("Sudoku: Score of %+v should be %d but is %d (%v)", test, test.score, score, err)
+		}
+	}
+}
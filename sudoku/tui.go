@@ -0,0 +1,346 @@
+package sudoku
+
+import (
+	"fmt"     // String formatting.
+	"io"      // Readers and writers.
+	"strings" // String manipulation.
+	"time"    // Timer refresh.
+)
+
+// The ANSI escape codes used to draw the full screen of @TUI.
+const (
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiAltScreen  = "\x1b[?1049h"
+	ansiMainScreen = "\x1b[?1049l"
+)
+
+// The names of the special keys read by @readKeys. Other keys are named by the
+// character they type.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyBackspace = "backspace"
+	keyDelete    = "delete"
+	keyInterrupt = "interrupt"
+)
+
+// The help line shown under the grid.
+const tuiHelp = "arrows move  1-9 write  x clear  p pencil  c candidates  u undo  r redo  h hint  space pause  q quit"
+
+// TUI plays a game on a terminal, drawing the grid with @Renderer.
+type TUI struct {
+	game *Game
+
+	// The cell under the cursor.
+	cursor Cell
+
+	// Digits toggle candidates instead of writing values.
+	pencil bool
+
+	// Draw the candidates of every cell, see CandidateMode.
+	showCandidates bool
+
+	// The hint being shown and how much of it is revealed, see @Hint.Text.
+	hint      *Hint
+	hintLevel int
+
+	// A message for the player, like the error of the last move.
+	message string
+}
+
+// Returns a terminal UI playing the game.
+func NewTUI(game *Game) *TUI {
+	return &TUI{game: game}
+}
+
+// Returns the time as minutes and seconds, like 05:07.
+func formatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+// Applies the key pressed by the player. Returns false if the player quits.
+func (tui *TUI) handleKey(key string) bool {
+	game := tui.game
+	x, y := tui.cursor.Row, tui.cursor.Column
+
+	tui.message = ""
+
+	if game.IsPaused() && !game.IsComplete() && !game.IsLost() {
+		switch key {
+		case "q", keyInterrupt:
+			return false
+		case " ":
+			game.Resume()
+		}
+
+		return true
+	}
+
+	var err error
+	moved := false
+
+	switch key {
+	case "q", keyInterrupt:
+		return false
+	case keyUp:
+		tui.cursor.Row = (x + 8) % 9
+	case keyDown:
+		tui.cursor.Row = (x + 1) % 9
+	case keyLeft:
+		tui.cursor.Column = (y + 8) % 9
+	case keyRight:
+		tui.cursor.Column = (y + 1) % 9
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		val := int(key[0] - '0')
+
+		if tui.pencil {
+			err = game.ToggleCandidate(x, y, val)
+		} else {
+			err = game.SetValue(x, y, val)
+		}
+
+		moved = true
+	case "x", "0", keyBackspace, keyDelete:
+		if tui.pencil {
+			err = game.SetCandidates(x, y, nil)
+		} else {
+			err = game.ClearValue(x, y)
+		}
+
+		moved = true
+	case "p":
+		tui.pencil = !tui.pencil
+		tui.showCandidates = tui.showCandidates || tui.pencil
+	case "c":
+		tui.showCandidates = !tui.showCandidates
+	case "u":
+		moved = game.Undo()
+	case "r":
+		moved = game.Redo()
+	case "h":
+		tui.nextHint()
+	case " ":
+		game.Pause()
+	}
+
+	if err != nil {
+		tui.message = err.Error()
+	}
+
+	// A move makes the hint stale.
+	if moved {
+		tui.hint = nil
+	}
+
+	return true
+}
+
+// Reveals more of the hint being shown, or takes a new one.
+func (tui *TUI) nextHint() {
+	if tui.hint != nil && tui.hintLevel < 3 {
+		tui.hintLevel++
+		return
+	}
+
+	hint, err := tui.game.NextHint()
+	if err != nil {
+		tui.hint = nil
+		tui.message = err.Error()
+		return
+	}
+
+	tui.hint = &hint
+	tui.hintLevel = 1
+}
+
+// Returns the whole screen, with lines separated by "\n".
+func (tui *TUI) view() string {
+	var b strings.Builder
+
+	game := tui.game
+	sudoku := game.GetSudoku()
+
+	status := "Time " + formatDuration(game.Elapsed())
+	status += fmt.Sprintf("  Mistakes %d", game.GetMistakes())
+
+	if game.mistakeLimit > 0 {
+		status += fmt.Sprintf("/%d", game.mistakeLimit)
+	}
+
+	status += fmt.Sprintf("  Hints %d", game.GetHints())
+
+	if tui.pencil {
+		status += "  [pencil]"
+	}
+
+	b.WriteString(status + "\n")
+
+	if game.IsPaused() && !game.IsComplete() && !game.IsLost() {
+		b.WriteString("\nPaused, press space to resume.\n")
+		return b.String()
+	}
+
+	renderer := Renderer{Color: true, Cursor: &tui.cursor}
+
+	if tui.showCandidates {
+		renderer.Mode = CandidateMode
+	}
+
+	if tui.hint != nil {
+		renderer.Marked = tui.hint.Highlights(tui.hintLevel)
+	}
+
+	b.WriteString(renderer.Render(&sudoku))
+
+	switch {
+	case game.IsComplete():
+		b.WriteString("Solved in " + formatDuration(game.Elapsed()) + "!")
+
+		if score, err := game.Score(); err == nil {
+			b.WriteString(fmt.Sprintf(" Score %d.", score))
+		}
+	case game.IsLost():
+		b.WriteString("Mistake limit reached.")
+	case tui.message != "":
+		b.WriteString(tui.message)
+	case tui.hint != nil:
+		b.WriteString(tui.hint.Text(tui.hintLevel))
+	}
+
+	b.WriteString("\n" + tuiHelp + "\n")
+
+	return b.String()
+}
+
+// Reads the keys typed on the terminal and sends their names to keys, see
+// @keyUp for the names of the special keys. Closes keys when r ends. Returns
+// once done is closed, at the latest after the next read of r, so no key is
+// left waiting for a reader which is gone.
+func readKeys(r io.Reader, keys chan<- string, done <-chan struct{}) {
+	defer close(keys)
+
+	buf := make([]byte, 64)
+	var pending []byte
+
+	for {
+		n, err := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+
+		for len(pending) > 0 {
+			key, size := decodeKey(pending)
+			if size == 0 {
+				break
+			}
+
+			pending = pending[size:]
+
+			if key != "" {
+				select {
+				case keys <- key:
+				case <-done:
+					return
+				}
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Returns the name of the first key of the input and the bytes it takes, or a
+// size of 0 if the input ends in the middle of an escape sequence. Unknown
+// escape sequences have an empty name.
+func decodeKey(input []byte) (string, int) {
+	switch input[0] {
+	case 0x03:
+		return keyInterrupt, 1
+	case 0x7f, 0x08:
+		return keyBackspace, 1
+	case 0x1b:
+	default:
+		return string(input[:1]), 1
+	}
+
+	if len(input) > 1 && input[1] != '[' && input[1] != 'O' {
+		return "", 1
+	}
+
+	if len(input) < 3 {
+		return "", 0
+	}
+
+	switch input[2] {
+	case 'A':
+		return keyUp, 3
+	case 'B':
+		return keyDown, 3
+	case 'C':
+		return keyRight, 3
+	case 'D':
+		return keyLeft, 3
+	}
+
+	// Sequences like "\x1b[3~" end with a letter or a tilde.
+	for k := 2; k < len(input); k++ {
+		if input[k] == '~' || (input[k] >= 'A' && input[k] <= 'Z') || (input[k] >= 'a' && input[k] <= 'z') {
+			if string(input[2:k+1]) == "3~" {
+				return keyDelete, k + 1
+			}
+
+			return "", k + 1
+		}
+	}
+
+	return "", 0
+}
+
+// Run plays the game on the terminal until the player quits. The terminal
+// behind in is switched to raw mode while playing, and the screen is redrawn
+// after every key and every second for the timer.
+func (tui *TUI) Run(in io.Reader, out io.Writer) error {
+	restore, err := makeRaw(in)
+	if err != nil {
+		return err
+	}
+
+	defer restore()
+
+	draw := func(screen string) {
+		// Raw mode doesn't return the carriage on new lines.
+		io.WriteString(out, ansiClear+strings.ReplaceAll(screen, "\n", "\r\n"))
+	}
+
+	io.WriteString(out, ansiAltScreen+ansiHideCursor)
+	defer io.WriteString(out, ansiShowCursor+ansiMainScreen)
+
+	keys := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+
+	go readKeys(in, keys, done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	draw(tui.view())
+
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || !tui.handleKey(key) {
+				return nil
+			}
+		case <-ticker.C:
+		}
+
+		draw(tui.view())
+	}
+}
@@ -0,0 +1,124 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadKeys(t *testing.T) {
+	input := "\x1b[A\x1b[B\x1b[C\x1b[D\x1b[3~\x1b[5~5x\x7f\x03"
+	expected := []string{keyUp, keyDown, keyRight, keyLeft, keyDelete, "5", "x", keyBackspace, keyInterrupt}
+
+	keys := make(chan string)
+	go readKeys(strings.NewReader(input), keys, make(chan struct{}))
+
+	var got []string
+	for key := range keys {
+		got = append(got, key)
+	}
+
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("Sudoku: Keys should be %v but are %v", expected, got)
+	}
+}
+
+func TestReadKeysDone(t *testing.T) {
+	keys := make(chan string)
+	done := make(chan struct{})
+
+	finished := make(chan struct{})
+
+	go func() {
+		readKeys(strings.NewReader("123"), keys, done)
+		close(finished)
+	}()
+
+	<-keys
+	close(done)
+
+	// The reader stops instead of waiting to send the other keys.
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Sudoku: The keys are still being read.")
+	}
+}
+
+func TestTUIRun(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	var out strings.Builder
+
+	// Inputs which are not a terminal are read without raw mode.
+	if err := NewTUI(NewGame(sudoku)).Run(strings.NewReader("\x1b[C\x1b[C4q"), &out); err != nil {
+		t.Fatalf("Sudoku: Can't play from a reader: %v", err)
+	}
+
+	if !strings.Contains(out.String(), tuiHelp) {
+		t.Errorf("Sudoku: The game was not drawn: %q", out.String())
+	}
+}
+
+func TestTUI(t *testing.T) {
+	sudoku, _ := Parse("530070000600195000098000060800060003400803001700020006060000280000419005000080079")
+	tui := NewTUI(NewGame(sudoku))
+
+	for _, key := range []string{keyRight, keyRight, "4"} {
+		tui.handleKey(key)
+	}
+
+	current := tui.game.GetSudoku()
+	if val, _ := current.GetValue(0, 2); val != 4 {
+		t.Errorf("Sudoku: Typing 4 on (0, 2) wrote %d", val)
+	}
+
+	// Pencil marks go to the candidates.
+	for _, key := range []string{keyRight, "p", "2", "6"} {
+		tui.handleKey(key)
+	}
+
+	current = tui.game.GetSudoku()
+	if candidates, _ := current.GetCandidates(0, 3); len(candidates) != 2 {
+		t.Errorf("Sudoku: Pencil marks of (0, 3) are %v", candidates)
+	}
+
+	tui.handleKey("u")
+
+	current = tui.game.GetSudoku()
+	if candidates, _ := current.GetCandidates(0, 3); len(candidates) != 1 {
+		t.Errorf("Sudoku: Undo left the pencil marks %v", candidates)
+	}
+
+	// Writing on a given shows the error.
+	tui.handleKey("p")
+	tui.handleKey(keyLeft)
+	tui.handleKey(keyLeft)
+	tui.handleKey(keyLeft)
+	tui.handleKey("1")
+
+	if view := tui.view(); !strings.Contains(view, "Sudoku:") {
+		t.Errorf("Sudoku: Error of the move is not shown:\n%s", view)
+	}
+
+	// Hints are revealed step by step.
+	tui.handleKey("h")
+	tui.handleKey("h")
+
+	if tui.game.GetHints() != 1 || !strings.Contains(tui.view(), " Look at ") {
+		t.Errorf("Sudoku: Second level of the hint is not shown:\n%s", tui.view())
+	}
+
+	// The grid is hidden while paused.
+	tui.handleKey(" ")
+
+	if view := tui.view(); !strings.Contains(view, "Paused") || strings.Contains(view, "╔") {
+		t.Errorf("Sudoku: Paused view shows the grid:\n%s", view)
+	}
+
+	tui.handleKey("4")
+	tui.handleKey(" ")
+
+	if tui.game.IsPaused() || tui.handleKey("q") {
+		t.Errorf("Sudoku: Can't resume and quit.")
+	}
+}
@@ -0,0 +1,316 @@
+package sudoku
+
+import (
+	"bufio"           // Buffered connections.
+	"crypto/rand"     // Masks and keys.
+	"crypto/sha1"     // Handshake.
+	"encoding/base64" // Handshake.
+	"encoding/binary" // Frame lengths.
+	"errors"          // Error handling.
+	"fmt"             // Requests.
+	"io"              // Readers and writers.
+	"net"             // Connections.
+	"net/http"        // Handshake.
+	"net/url"         // Dialing.
+	"strings"         // Header values.
+	"sync"            // Concurrent writes.
+	"time"            // Deadlines.
+)
+
+// The GUID every WebSocket handshake appends to the key, see RFC 6455.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The largest message accepted by @wsConn.ReadMessage.
+const wsMaxMessage = 64 << 10
+
+// The opcodes of the frames.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// A minimal WebSocket connection, enough to exchange text messages with a
+// browser or with another wsConn. Writes may come from several goroutines,
+// reads from a single one.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// Clients mask the frames they send, servers don't.
+	client bool
+
+	writing sync.Mutex
+}
+
+// Returns the Sec-WebSocket-Accept header of the key.
+func wsAccept(key string) string {
+	hash := sha1.Sum([]byte(key + wsGUID))
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// Returns true if the comma separated header has the token.
+func headerHas(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Checks that the HTTP request is a WebSocket handshake which can be upgraded,
+// see @wsHijack, and returns its key. An error response is written if not.
+func wsCheck(w http.ResponseWriter, r *http.Request) (string, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if r.Method != http.MethodGet || key == "" ||
+		!headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Sudoku: Expected a WebSocket handshake.", http.StatusBadRequest)
+		return "", errors.New("Sudoku: Expected a WebSocket handshake.")
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Sudoku: Unsupported WebSocket version.", http.StatusUpgradeRequired)
+		return "", errors.New("Sudoku: Unsupported WebSocket version.")
+	}
+
+	if _, ok := w.(http.Hijacker); !ok {
+		http.Error(w, "Sudoku: Can't upgrade the connection.", http.StatusInternalServerError)
+		return "", errors.New("Sudoku: Can't upgrade the connection.")
+	}
+
+	return key, nil
+}
+
+// Takes over the connection of a request which passed @wsCheck with the
+// given key and answers its handshake.
+func wsHijack(w http.ResponseWriter, key string) (*wsConn, error) {
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// The deadlines of the HTTP server would end the game after a request
+	// timeout.
+	conn.SetDeadline(time.Time{})
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAccept(key) + "\r\n\r\n"
+
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+// Opens a WebSocket connection to the ws:// URL.
+func wsDial(rawURL string) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "ws" {
+		return nil, errors.New("Sudoku: Only ws:// URLs are supported.")
+	}
+
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := fmt.Sprintf("GET %s HTTP/1.1\r\n"+
+		"Host: %s\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n", u.RequestURI(), u.Host, key)
+
+	if _, err := conn.Write([]byte(request)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusSwitchingProtocols || response.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		conn.Close()
+		return nil, fmt.Errorf("Sudoku: WebSocket handshake failed with %s.", response.Status)
+	}
+
+	return &wsConn{conn: conn, reader: reader, client: true}, nil
+}
+
+// Writes a frame with the opcode and the payload.
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	ws.writing.Lock()
+	defer ws.writing.Unlock()
+
+	header := []byte{0x80 | opcode, 0}
+	n := len(payload)
+
+	switch {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if ws.client {
+		mask := make([]byte, 4)
+		rand.Read(mask)
+
+		header[1] |= 0x80
+		header = append(header, mask...)
+
+		masked := make([]byte, n)
+		for k := range payload {
+			masked[k] = payload[k] ^ mask[k%4]
+		}
+
+		payload = masked
+	}
+
+	if _, err := ws.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reads a frame and returns its FIN bit, opcode and payload.
+func (ws *wsConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	// Every frame of a client is masked, and no frame of a server is.
+	if masked == ws.client {
+		return false, 0, nil, errors.New("Sudoku: WebSocket frame with the wrong masking.")
+	}
+
+	if length > wsMaxMessage {
+		return false, 0, nil, errors.New("Sudoku: WebSocket message too large.")
+	}
+
+	var mask [4]byte
+
+	if masked {
+		if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for k := range payload {
+			payload[k] ^= mask[k%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// Reads the next text or binary message, answering pings on the way. Returns
+// io.EOF once the other end closes the connection.
+func (ws *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsPing:
+			ws.writeFrame(wsPong, payload)
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			ws.writeFrame(wsClose, nil)
+			return nil, io.EOF
+		}
+
+		message = append(message, payload...)
+
+		if len(message) > wsMaxMessage {
+			return nil, errors.New("Sudoku: WebSocket message too large.")
+		}
+
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// Writes a text message.
+func (ws *wsConn) WriteMessage(message []byte) error {
+	return ws.writeFrame(wsText, message)
+}
+
+// Sends a close frame and closes the connection.
+func (ws *wsConn) Close() error {
+	ws.writeFrame(wsClose, nil)
+
+	return ws.conn.Close()
+}